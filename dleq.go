@@ -0,0 +1,145 @@
+package ristretto
+
+import (
+	"github.com/bwesterb/go-ristretto/edwards25519"
+)
+
+// Creates a non-interactive Chaum-Pedersen proof that log_G(A) == log_H(B)
+// for the secret x with A = x*G and B = x*H.  Returns the proof (c, s).
+//
+// The proof is made non-interactive with the Fiat-Shamir heuristic:
+// the challenge c is derived from G, H, A, B and the commitments
+// k*G and k*H for a random nonce k.  Verify with VerifyDLEQ().
+func ProveDLEQ(x *Scalar, G, H, A, B *Point) (c, s Scalar) {
+	var k Scalar
+	var R1, R2 Point
+	k.Rand()
+	R1.ScalarMult(G, &k)
+	R2.ScalarMult(H, &k)
+	dleqChallenge(&c, G, H, A, B, &R1, &R2)
+	s.MulAdd(&c, x, &k)
+	return
+}
+
+// Returns whether (c, s) is a valid proof created with ProveDLEQ()
+// that log_G(A) == log_H(B).
+func VerifyDLEQ(c, s *Scalar, G, H, A, B *Point) bool {
+	var R1, R2, tmp Point
+	var c2 Scalar
+
+	// R1 = s*G - c*A and R2 = s*H - c*B
+	R1.PublicScalarMult(G, s)
+	R1.Sub(&R1, tmp.PublicScalarMult(A, c))
+	R2.PublicScalarMult(H, s)
+	R2.Sub(&R2, tmp.PublicScalarMult(B, c))
+
+	dleqChallenge(&c2, G, H, A, B, &R1, &R2)
+	return c.Equals(&c2)
+}
+
+// Sets c to the Fiat-Shamir challenge for a DLEQ proof.  Returns c.
+func dleqChallenge(c *Scalar, G, H, A, B, R1, R2 *Point) *Scalar {
+	var buf [32]byte
+	transcript := make([]byte, 0, 16+6*32)
+	transcript = append(transcript, "ristretto-dleq-1"...)
+	for _, p := range []*Point{G, H, A, B, R1, R2} {
+		p.BytesInto(&buf)
+		transcript = append(transcript, buf[:]...)
+	}
+	return c.Derive(transcript)
+}
+
+// The statement log_G(A) == log_H(B) of a DLEQ proof.
+type DLEQStatement struct {
+	G, H, A, B Point
+}
+
+// A DLEQ proof in batchable form: instead of the challenge c it contains
+// the commitments R1 = k*G and R2 = k*H, from which the verifier
+// recomputes c.  The response is S = c*x + k, just like for ProveDLEQ().
+//
+// It is 32 bytes longer than (c, s), but it can be verified together
+// with others using BatchVerifyDLEQ().
+type DLEQProof struct {
+	R1, R2 Point
+	S      Scalar
+}
+
+// Sets pf to a proof of the statement st for the secret x with
+// st.A = x*st.G and st.B = x*st.H.  Returns pf.
+func (pf *DLEQProof) Prove(x *Scalar, st *DLEQStatement) *DLEQProof {
+	var k, c Scalar
+	k.Rand()
+	pf.R1.ScalarMult(&st.G, &k)
+	pf.R2.ScalarMult(&st.H, &k)
+	dleqChallenge(&c, &st.G, &st.H, &st.A, &st.B, &pf.R1, &pf.R2)
+	pf.S.MulAdd(&c, x, &k)
+	return pf
+}
+
+// Returns whether pf is a valid proof of the statement st.
+func (pf *DLEQProof) Verify(st *DLEQStatement) bool {
+	var c Scalar
+	var lhs, rhs, tmp Point
+	dleqChallenge(&c, &st.G, &st.H, &st.A, &st.B, &pf.R1, &pf.R2)
+
+	// S*G == R1 + c*A and S*H == R2 + c*B
+	lhs.PublicScalarMult(&st.G, &pf.S)
+	rhs.Add(&pf.R1, tmp.PublicScalarMult(&st.A, &c))
+	if !lhs.Equals(&rhs) {
+		return false
+	}
+	lhs.PublicScalarMult(&st.H, &pf.S)
+	rhs.Add(&pf.R2, tmp.PublicScalarMult(&st.B, &c))
+	return lhs.Equals(&rhs)
+}
+
+// Verifies the proofs for the statements at once.  proofs[i] should prove
+// stmts[i].  Returns whether all proofs are valid and, per proof, whether
+// it is valid.
+//
+// The verification equations of all proofs are combined with random
+// weights into a single multi-scalar multiplication, which is much faster
+// than verifying the proofs one by one.  Only if that check fails are the
+// proofs verified individually to find the invalid ones.
+//
+// Returns false and nil if the number of statements and proofs differ.
+func BatchVerifyDLEQ(stmts []DLEQStatement, proofs []DLEQProof) (bool, []bool) {
+	var msm edwards25519.IncrementalMSM
+	var c, z, w, t Scalar
+	var buf [32]byte
+	if len(stmts) != len(proofs) {
+		return false, nil
+	}
+
+	// sum_i z_i (S_i G_i - R1_i - c_i A_i) + w_i (S_i H_i - R2_i - c_i B_i)
+	add := func(s *Scalar, p *Point) {
+		s.BytesInto(&buf)
+		msm.Add(&buf, p.e())
+	}
+	for i := 0; i < len(stmts); i++ {
+		st, pf := &stmts[i], &proofs[i]
+		dleqChallenge(&c, &st.G, &st.H, &st.A, &st.B, &pf.R1, &pf.R2)
+		z.Rand()
+		w.Rand()
+		add(t.Mul(&z, &pf.S), &st.G)
+		add(t.Neg(&z), &pf.R1)
+		add(t.Neg(t.Mul(&z, &c)), &st.A)
+		add(t.Mul(&w, &pf.S), &st.H)
+		add(t.Neg(&w), &pf.R2)
+		add(t.Neg(t.Mul(&w, &c)), &st.B)
+	}
+
+	valid := make([]bool, len(stmts))
+	if (*Point)(msm.Result()).Equals(new(Point).SetZero()) {
+		for i := 0; i < len(valid); i++ {
+			valid[i] = true
+		}
+		return true, valid
+	}
+
+	for i := 0; i < len(stmts); i++ {
+		valid[i] = proofs[i].Verify(&stmts[i])
+	}
+	return false, valid
+}
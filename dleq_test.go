@@ -0,0 +1,117 @@
+package ristretto_test
+
+import (
+	"testing"
+
+	"github.com/bwesterb/go-ristretto"
+)
+
+func TestDLEQ(t *testing.T) {
+	var x, x2 ristretto.Scalar
+	var G, H, A, B, B2 ristretto.Point
+	for i := 0; i < 100; i++ {
+		x.Rand()
+		G.Rand()
+		H.Rand()
+		A.ScalarMult(&G, &x)
+		B.ScalarMult(&H, &x)
+		c, s := ristretto.ProveDLEQ(&x, &G, &H, &A, &B)
+		if !ristretto.VerifyDLEQ(&c, &s, &G, &H, &A, &B) {
+			t.Fatalf("DLEQ proof for %v does not verify", x)
+		}
+
+		// Soundness: a different discrete log for B
+		x2.Rand()
+		B2.ScalarMult(&H, &x2)
+		c, s = ristretto.ProveDLEQ(&x, &G, &H, &A, &B2)
+		if ristretto.VerifyDLEQ(&c, &s, &G, &H, &A, &B2) {
+			t.Fatalf("DLEQ proof for unequal logs verifies")
+		}
+
+		// Soundness: the proof is bound to the statement
+		c, s = ristretto.ProveDLEQ(&x, &G, &H, &A, &B)
+		if ristretto.VerifyDLEQ(&c, &s, &H, &G, &A, &B) {
+			t.Fatalf("DLEQ proof verifies for swapped generators")
+		}
+		s.Add(&s, &x2)
+		if ristretto.VerifyDLEQ(&c, &s, &G, &H, &A, &B) {
+			t.Fatalf("DLEQ proof with tampered response verifies")
+		}
+	}
+}
+
+func BenchmarkVerifyDLEQ(b *testing.B) {
+	var x ristretto.Scalar
+	var G, H, A, B ristretto.Point
+	x.Rand()
+	G.Rand()
+	H.Rand()
+	A.ScalarMult(&G, &x)
+	B.ScalarMult(&H, &x)
+	c, s := ristretto.ProveDLEQ(&x, &G, &H, &A, &B)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		ristretto.VerifyDLEQ(&c, &s, &G, &H, &A, &B)
+	}
+}
+
+func TestBatchVerifyDLEQ(t *testing.T) {
+	var x ristretto.Scalar
+	stmts := make([]ristretto.DLEQStatement, 10)
+	proofs := make([]ristretto.DLEQProof, 10)
+	for i := 0; i < len(stmts); i++ {
+		x.Rand()
+		stmts[i].G.Rand()
+		stmts[i].H.Rand()
+		stmts[i].A.ScalarMult(&stmts[i].G, &x)
+		stmts[i].B.ScalarMult(&stmts[i].H, &x)
+		proofs[i].Prove(&x, &stmts[i])
+		if !proofs[i].Verify(&stmts[i]) {
+			t.Fatalf("DLEQProof does not verify")
+		}
+	}
+
+	ok, valid := ristretto.BatchVerifyDLEQ(stmts, proofs)
+	if !ok {
+		t.Fatalf("BatchVerifyDLEQ rejects valid proofs: %v", valid)
+	}
+	for i := 0; i < len(valid); i++ {
+		if !valid[i] {
+			t.Fatalf("BatchVerifyDLEQ marks valid proof %d as invalid", i)
+		}
+	}
+
+	// Break the fourth statement
+	stmts[3].B.Rand()
+	ok, valid = ristretto.BatchVerifyDLEQ(stmts, proofs)
+	if ok {
+		t.Fatalf("BatchVerifyDLEQ accepts an invalid proof")
+	}
+	for i := 0; i < len(valid); i++ {
+		if valid[i] != (i != 3) {
+			t.Fatalf("BatchVerifyDLEQ() = %v; expected only 3 to be invalid", valid)
+		}
+	}
+
+	if ok, _ := ristretto.BatchVerifyDLEQ(stmts, proofs[:9]); ok {
+		t.Fatalf("BatchVerifyDLEQ accepts mismatched lengths")
+	}
+}
+
+func BenchmarkBatchVerifyDLEQ(b *testing.B) {
+	var x ristretto.Scalar
+	stmts := make([]ristretto.DLEQStatement, 64)
+	proofs := make([]ristretto.DLEQProof, 64)
+	for i := 0; i < len(stmts); i++ {
+		x.Rand()
+		stmts[i].G.Rand()
+		stmts[i].H.Rand()
+		stmts[i].A.ScalarMult(&stmts[i].G, &x)
+		stmts[i].B.ScalarMult(&stmts[i].H, &x)
+		proofs[i].Prove(&x, &stmts[i])
+	}
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		ristretto.BatchVerifyDLEQ(stmts, proofs)
+	}
+}
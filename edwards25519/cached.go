@@ -0,0 +1,66 @@
+package edwards25519
+
+// (Y+X, Y-X, Z, 2dT) for a point (X:Y:Z:T) in extended coordinates.
+//
+// Like NielsPoint, but without the inversion to normalize Z, so it is cheap
+// to compute from an ExtendedPoint.  Adding it to an ExtendedPoint costs
+// one multiplication more than adding a NielsPoint.
+type CachedPoint struct {
+	YPlusX, YMinusX, Z, T2D FieldElement
+}
+
+// Set p to zero, the neutral element.  Return p.
+func (p *CachedPoint) SetZero() *CachedPoint {
+	p.YPlusX.SetOne()
+	p.YMinusX.SetOne()
+	p.Z.SetOne()
+	p.T2D.SetZero()
+	return p
+}
+
+// Sets p to q.  Returns p.
+func (p *CachedPoint) SetExtended(q *ExtendedPoint) *CachedPoint {
+	p.YPlusX.Add(&q.Y, &q.X)
+	p.YMinusX.Sub(&q.Y, &q.X)
+	p.Z.Set(&q.Z)
+	p.T2D.Mul(&q.T, &fe2D)
+	return p
+}
+
+// Set p to q if b == 1.  Assumes b is 0 or 1.  Returns p.
+func (p *CachedPoint) ConditionalSet(q *CachedPoint, b int32) *CachedPoint {
+	p.YPlusX.ConditionalSet(&q.YPlusX, b)
+	p.YMinusX.ConditionalSet(&q.YMinusX, b)
+	p.Z.ConditionalSet(&q.Z, b)
+	p.T2D.ConditionalSet(&q.T2D, b)
+	return p
+}
+
+// Set p to -q.  Returns p.
+func (p *CachedPoint) Neg(q *CachedPoint) *CachedPoint {
+	var t FieldElement
+	t.Set(&q.YPlusX)
+	p.YPlusX.Set(&q.YMinusX)
+	p.YMinusX.Set(&t)
+	p.Z.Set(&q.Z)
+	p.T2D.Neg(&q.T2D)
+	return p
+}
+
+// Sets p to q+r.  Returns p.
+func (p *CompletedPoint) AddExtendedCached(q *ExtendedPoint, r *CachedPoint) *CompletedPoint {
+	var t0 FieldElement
+
+	p.X.add(&q.Y, &q.X)
+	p.Y.sub(&q.Y, &q.X)
+	p.Z.Mul(&p.X, &r.YPlusX)
+	p.Y.Mul(&p.Y, &r.YMinusX)
+	p.T.Mul(&r.T2D, &q.T)
+	t0.Mul(&q.Z, &r.Z)
+	t0.add(&t0, &t0)
+	p.X.sub(&p.Z, &p.Y)
+	p.Y.add(&p.Z, &p.Y)
+	p.Z.add(&t0, &p.T)
+	p.T.sub(&t0, &p.T)
+	return p
+}
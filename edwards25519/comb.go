@@ -0,0 +1,68 @@
+package edwards25519
+
+// Returns a table for fixed-base scalar multiplication of q with signed
+// digits of the given width, see CombScalarMult().
+//
+// Row i holds the multiples j*2^(width*i)*q for j = 1, ..., 2^(width-1)
+// followed by their negations, so that every signed digit can be looked
+// up without a conditional negation.  There are ceil(256/width) rows.
+// Requires 2 <= width <= 7.
+func BuildCombTable(q *ExtendedPoint, width uint) [][]CachedPoint {
+	var base, multiple ExtendedPoint
+	var cp CompletedPoint
+	if width < 2 || width > 7 {
+		panic("edwards25519: BuildCombTable: width must be between 2 and 7")
+	}
+	half := 1 << (width - 1)
+	table := make([][]CachedPoint, (256+width-1)/width)
+	base.Set(q)
+	for i := range table {
+		row := make([]CachedPoint, 2*half)
+		row[0].SetExtended(&base)
+		row[half].Neg(&row[0])
+		multiple.Set(&base)
+		for j := 1; j < half; j++ {
+			multiple.SetCompleted(cp.AddExtendedCached(&multiple, &row[0]))
+			row[j].SetExtended(&multiple)
+			row[half+j].Neg(&row[j])
+		}
+		table[i] = row
+		base.DoubleN(&base, int(width))
+	}
+	return table
+}
+
+// Set p to s * q, where table was computed for q using BuildCombTable().
+// Requires s < 2^253, which is the case for reduced scalars.  Returns p.
+//
+// As every row of the table covers one digit, no doublings are needed.
+// The running time does not depend on s.
+func (p *ExtendedPoint) CombScalarMult(table [][]CachedPoint,
+	s *[32]byte) *ExtendedPoint {
+	var digits [128]int8
+	var cached CachedPoint
+	var cp CompletedPoint
+	width := uint(1)
+	for 1<<width < len(table[0]) {
+		width++
+	}
+	RecodeScalarWindow(s, width, digits[:len(table)])
+
+	p.SetZero()
+	for i, row := range table {
+		selectComb(&cached, row, int32(digits[i]))
+		p.SetCompleted(cp.AddExtendedCached(p, &cached))
+	}
+	return p
+}
+
+// Sets p to the entry of the comb table row for the signed digit d, or to
+// zero if d is zero, touching every entry.
+func selectComb(p *CachedPoint, row []CachedPoint, d int32) {
+	half := len(row) / 2
+	p.SetZero()
+	for j := 0; j < half; j++ {
+		p.ConditionalSet(&row[j], equal15(d, int32(j+1)))
+		p.ConditionalSet(&row[half+j], equal15(d, -int32(j+1)))
+	}
+}
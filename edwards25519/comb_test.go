@@ -0,0 +1,63 @@
+package edwards25519_test
+
+import (
+	"testing"
+
+	"github.com/bwesterb/go-ristretto/edwards25519"
+)
+
+func TestBuildCombTable(t *testing.T) {
+	var q, base, want, got, zero edwards25519.ExtendedPoint
+	var cp edwards25519.CompletedPoint
+	var s [32]byte
+	q.SetBase()
+	zero.SetZero()
+	for width := uint(2); width <= 7; width++ {
+		table := edwards25519.BuildCombTable(&q, width)
+		half := 1 << (width - 1)
+		if len(table) != int((256+width-1)/width) || len(table[0]) != 2*half {
+			t.Fatalf("BuildCombTable(%d) has wrong dimensions", width)
+		}
+
+		// Row i should hold the multiples of base = 2^(width*i) q
+		base.Set(&q)
+		for i := range table {
+			for j := 0; j < half; j++ {
+				s[0] = byte(j + 1)
+				want.ScalarMult(&base, &s)
+				got.SetCompleted(cp.AddExtendedCached(&zero, &table[i][j]))
+				if got.RistrettoEqualsI(&want) != 1 {
+					t.Fatalf("BuildCombTable(%d)[%d][%d] is wrong", width, i, j)
+				}
+				got.SetCompleted(cp.AddExtendedCached(&want, &table[i][half+j]))
+				if got.RistrettoEqualsI(&zero) != 1 {
+					t.Fatalf("BuildCombTable(%d)[%d][%d] is not the negation",
+						width, i, half+j)
+				}
+			}
+			base.DoubleN(&base, int(width))
+		}
+	}
+}
+
+func TestCombScalarMult(t *testing.T) {
+	var buf, s [32]byte
+	var fe edwards25519.FieldElement
+	var cp edwards25519.CompletedPoint
+	var q, p1, p2 edwards25519.ExtendedPoint
+	for width := uint(2); width <= 7; width++ {
+		rnd.Read(buf[:])
+		fe.SetBytes(&buf)
+		q.SetCompleted(cp.SetRistrettoElligator2(&fe))
+		table := edwards25519.BuildCombTable(&q, width)
+		for i := 0; i < 100; i++ {
+			rnd.Read(s[:])
+			s[31] &= 15
+			p1.ScalarMult(&q, &s)
+			p2.CombScalarMult(table, &s)
+			if p1.RistrettoEqualsI(&p2) != 1 {
+				t.Fatalf("CombScalarMult(%d, %x) = %v != %v", width, s, &p2, &p1)
+			}
+		}
+	}
+}
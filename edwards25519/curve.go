@@ -1,11 +1,20 @@
 // Go implementation of the elliptic curve Edwards25519 of which the
 // Ristretto group is a subquotient.
+//
+// Scalars are passed around as their 32-byte little-endian encoding.  This
+// package does no arithmetic modulo the group order l: use ristretto.Scalar
+// for that, whose BytesInto() gives the encoding expected here.
 package edwards25519
 
 import (
+	"crypto/rand"
 	"crypto/subtle"
 	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"sync"
 )
 
 // (X:Y:Z:T) satisfying x=X/Z, y=Y/Z, X*Y=Z*T.  Aka P3.
@@ -72,6 +81,13 @@ func (p *ExtendedPoint) SetBase() *ExtendedPoint {
 	return p.Set(&epBase)
 }
 
+// Set p to the basepoint if b == 1 and to zero if b == 0 in constant time.
+// Assumes b is 0 or 1.  Returns p.
+func (p *ExtendedPoint) SetBaseIf(b int32) *ExtendedPoint {
+	p.SetZero()
+	return p.ConditionalSet(&epBase, b)
+}
+
 // Set p to q.  Returns p.
 func (p *ExtendedPoint) Set(q *ExtendedPoint) *ExtendedPoint {
 	p.X.Set(&q.X)
@@ -81,6 +97,30 @@ func (p *ExtendedPoint) Set(q *ExtendedPoint) *ExtendedPoint {
 	return p
 }
 
+// Returns a newly allocated copy of p.
+func (p *ExtendedPoint) Clone() *ExtendedPoint {
+	return new(ExtendedPoint).Set(p)
+}
+
+// Returns a newly allocated copy of p.
+func (p *ProjectivePoint) Clone() *ProjectivePoint {
+	var ret ProjectivePoint
+	ret.X.Set(&p.X)
+	ret.Y.Set(&p.Y)
+	ret.Z.Set(&p.Z)
+	return &ret
+}
+
+// Returns a newly allocated copy of p.
+func (p *CompletedPoint) Clone() *CompletedPoint {
+	var ret CompletedPoint
+	ret.X.Set(&p.X)
+	ret.Y.Set(&p.Y)
+	ret.Z.Set(&p.Z)
+	ret.T.Set(&p.T)
+	return &ret
+}
+
 // Set p to q if b == 1.  Assumes b is 0 or 1.   Returns p.
 func (p *ExtendedPoint) ConditionalSet(q *ExtendedPoint, b int32) *ExtendedPoint {
 	p.X.ConditionalSet(&q.X, b)
@@ -90,7 +130,32 @@ func (p *ExtendedPoint) ConditionalSet(q *ExtendedPoint, b int32) *ExtendedPoint
 	return p
 }
 
+// Set p to -p if b == 1 and leave p unchanged if b == 0.  Assumes b is 0
+// or 1.  Returns p.
+func (p *ExtendedPoint) ConditionalNegate(b int32) *ExtendedPoint {
+	p.X.ConditionalNegate(b)
+	p.T.ConditionalNegate(b)
+	return p
+}
+
+// Set p to p + q if b == 1 and leave p unchanged (up to representation) if
+// b == 0.  Assumes b is 0 or 1.  Returns p.
+//
+// Both cases perform the same addition, of either q or zero, so the
+// running time does not depend on b.
+func (p *ExtendedPoint) ConditionalAdd(q *ExtendedPoint, b int32) *ExtendedPoint {
+	var t ExtendedPoint
+	t.SetZero()
+	t.ConditionalSet(q, b)
+	return p.Add(p, &t)
+}
+
 // Sets p to q+r.  Returns p
+//
+// Unlike ExtendedPoint.Add(), this does not convert the sum back into
+// extended coordinates.  In tight loops where the sum is doubled next,
+// use ProjectivePoint.SetCompleted() and CompletedPoint.DoubleProjective()
+// instead, which saves a multiplication.
 func (p *CompletedPoint) AddExtended(q, r *ExtendedPoint) *CompletedPoint {
 	var a, b, c, d, t FieldElement
 
@@ -194,6 +259,30 @@ func (p *ExtendedPoint) Double(q *ExtendedPoint) *ExtendedPoint {
 	return p
 }
 
+// Set p to 2^n * q, that is: q doubled n times.  Returns p.
+//
+// The intermediate results are kept in projective coordinates, which is
+// cheaper than calling Double() n times.
+func (p *ExtendedPoint) DoubleN(q *ExtendedPoint, n int) *ExtendedPoint {
+	var pp ProjectivePoint
+	var cp CompletedPoint
+	if n <= 0 {
+		return p.Set(q)
+	}
+	cp.DoubleExtended(q)
+	for i := 1; i < n; i++ {
+		pp.SetCompleted(&cp)
+		cp.DoubleProjective(&pp)
+	}
+	return p.SetCompleted(&cp)
+}
+
+// Set p to 8 * q, where 8 is the cofactor of Edwards25519.  The result
+// lies in the prime-order subgroup.  Returns p.
+func (p *ExtendedPoint) MulByCofactor(q *ExtendedPoint) *ExtendedPoint {
+	return p.DoubleN(q, 3)
+}
+
 // Set p to q + r. Returns p.
 func (p *ExtendedPoint) Add(q, r *ExtendedPoint) *ExtendedPoint {
 	var tmp CompletedPoint
@@ -222,9 +311,37 @@ func (p *ExtendedPoint) SetCompleted(q *CompletedPoint) *ExtendedPoint {
 // Set p to a point corresponding to the encoded group element of
 // the ristretto group.  Returns whether the buffer encoded a group element.
 func (p *ExtendedPoint) SetRistretto(buf *[32]byte) bool {
+	ret := decodeRistretto(buf, &p.X, &p.Y, &p.T, nil)
+	p.Z.SetOne()
+	p.X.ConditionalSet(&feZero, ret)
+	p.Y.ConditionalSet(&feZero, ret)
+	p.Z.ConditionalSet(&feZero, ret)
+	p.T.ConditionalSet(&feZero, ret)
+	return ret == 0
+}
+
+// Returns whether buf is the Ristretto encoding of a group element.
+//
+// This runs the same checks as SetRistretto(), in constant time, but
+// does not assemble the point.  As those checks include the inverse
+// square root, which dominates the cost of decoding, this is only
+// slightly faster than SetRistretto().
+func IsValidRistrettoEncoding(buf *[32]byte) bool {
+	var x, y, t FieldElement
+	return decodeRistretto(buf, &x, &y, &t, nil) == 0
+}
+
+// Decodes the Ristretto encoding buf into the affine coordinates x and y
+// of a point and their product t.  Returns 0 if buf is valid and
+// non-zero otherwise, in which case x, y and t are garbage.
+//
+// If encIsr is not nil, it is set to ±1/sqrt((1 - y^2) t^2), the inverse
+// square root that is needed to encode the point (x, y) or (-x, y) again.
+// This takes a few multiplications, but no second inverse square root.
+func decodeRistretto(buf *[32]byte, x, y, t, encIsr *FieldElement) int32 {
 	var s, s2, chk, yDen, yNum, yDen2, xDen2, isr, xDenInv FieldElement
-	var yDenInv, t FieldElement
-	var b, ret int32
+	var yDenInv, w, wInv FieldElement
+	var ret int32
 	var buf2 [32]byte
 
 	s.SetBytes(buf)
@@ -243,30 +360,229 @@ func (p *ExtendedPoint) SetRistretto(buf *[32]byte) bool {
 	xDen2.add(&xDen2, &yDen2)
 	xDen2.Neg(&xDen2)
 	t.Mul(&xDen2, &yDen2)
-	isr.InvSqrt(&t)
+	if encIsr == nil {
+		isr.InvSqrt(t)
+	} else {
+		// As 1 - y^2 = (2s/(1 + s^2))^2 and xy = ±2s^2 isr yNum, we have
+		// encIsr = ±isr t yDen/w with w = 4 s^2 yNum.  We get 1/w for free
+		// by computing isr' = ±1/(w sqrt(t)) instead of isr = ±1/sqrt(t):
+		// then isr = isr' w and 1/w = isr'^2 w t.  If w is zero, then
+		// either s is zero, which encodes zero, or buf is invalid.
+		w.Mul(&s2, &yNum)
+		w.Add(&w, &w)
+		w.Add(&w, &w)
+		w.ConditionalSet(&feOne, 1-w.IsNonZeroI())
+		wInv.Square(&w)
+		wInv.Mul(&wInv, t)
+		isr.InvSqrt(&wInv)
+		wInv.Square(&isr)
+		wInv.Mul(&wInv, &w)
+		wInv.Mul(&wInv, t)
+		isr.Mul(&isr, &w)
+		encIsr.Mul(&isr, t)
+		encIsr.Mul(encIsr, &yDen)
+		encIsr.Mul(encIsr, &wInv)
+	}
 	chk.Square(&isr)
-	chk.Mul(&chk, &t)
+	chk.Mul(&chk, t)
 	ret |= 1 - chk.IsOneI()
 	xDenInv.Mul(&isr, &yDen)
 	yDenInv.Mul(&xDenInv, &isr)
 	yDenInv.Mul(&yDenInv, &xDen2)
-	p.X.Mul(&s, &xDenInv)
-	p.X.add(&p.X, &p.X)
-	b = p.X.IsNegativeI()
-	t.Neg(&p.X)
-	p.X.ConditionalSet(&t, b)
-	p.Y.Mul(&yNum, &yDenInv)
+	x.Mul(&s, &xDenInv)
+	x.add(x, x)
+	x.ConditionalNegate(x.IsNegativeI())
+	y.Mul(&yNum, &yDenInv)
+	t.Mul(x, y)
+	ret |= t.IsNegativeI()
+	ret |= 1 - y.IsNonZeroI()
+	return ret
+}
+
+// Sets p to 8 times the Edwards point encoded in buf, which is the
+// standard compressed encoding of Ed25519: the y-coordinate with the sign
+// of x in the highest bit.  Returns whether buf encodes a point.
+//
+// The result lies in the prime-order subgroup, so any small-order
+// component of the encoded point is cleared: all encodings that differ
+// by a point of small order give the same result.  Use this to import
+// Edwards points from sources that do not use the Ristretto encoding.
+// Rejects non-canonical encodings.
+func (p *ExtendedPoint) SetEdwardsClearTorsion(buf *[32]byte) bool {
+	ok := p.setEdwards(buf)
+	p.MulByCofactor(p)
+	return ok
+}
+
+// Set p to the Edwards point encoded in buf in the standard compressed
+// encoding.  If buf does not encode a point, sets p to zero.  Returns
+// whether buf encodes a point.
+func (p *ExtendedPoint) setEdwards(buf *[32]byte) bool {
+	var y, x, u, v FieldElement
+	var yBuf, buf2 [32]byte
+	var ret int32
+
+	copy(yBuf[:], buf[:])
+	yBuf[31] &= 127
+	sign := int32(buf[31] >> 7)
+
+	// ensures 0 <= y < 2^255-19
+	y.SetBytes(&yBuf)
+	y.BytesInto(&buf2)
+	ret = int32(1 - subtle.ConstantTimeCompare(yBuf[:], buf2[:]))
+
+	// x^2 = (y^2 - 1) / (d y^2 + 1)
+	u.Square(&y)
+	v.Mul(&u, &feD)
+	u.sub(&u, &feOne)
+	v.add(&v, &feOne)
+	ret |= 1 - x.SqrtRatioI(&u, &v)
+
+	// x = 0 has no negative counterpart
+	ret |= sign & (1 - x.IsNonZeroI())
+	x.ConditionalNegate(sign)
+
+	p.X.Set(&x)
+	p.Y.Set(&y)
 	p.Z.SetOne()
-	p.T.Mul(&p.X, &p.Y)
-	ret |= p.T.IsNegativeI()
-	ret |= 1 - p.Y.IsNonZeroI()
+	p.T.Mul(&x, &y)
+
 	p.X.ConditionalSet(&feZero, ret)
-	p.Y.ConditionalSet(&feZero, ret)
-	p.Z.ConditionalSet(&feZero, ret)
+	p.Y.ConditionalSet(&feOne, ret)
+	p.Z.ConditionalSet(&feOne, ret)
 	p.T.ConditionalSet(&feZero, ret)
 	return ret == 0
 }
 
+// Encoding of a point of order 8, which generates the torsion subgroup.
+var torsionGenerator = [32]byte{
+	0xc7, 0x17, 0x6a, 0x70, 0x3d, 0x4d, 0xd8, 0x4f,
+	0xba, 0x3c, 0x0b, 0x76, 0x0d, 0x10, 0x67, 0x0f,
+	0x2a, 0x20, 0x53, 0xfa, 0x2c, 0x39, 0xcc, 0xc6,
+	0x4e, 0xc7, 0xfd, 0x77, 0x92, 0xac, 0x03, 0x7a,
+}
+
+// The points returned by TorsionPoints(), computed on first use.
+var (
+	torsionPoints     [8]ExtendedPoint
+	torsionPointsOnce sync.Once
+)
+
+// Returns the eight points of small order: ret[i] is i*T for a fixed
+// point T of order 8, so ret[0] is zero and ret[4] = (0,-1).  These are
+// exactly the points that the cofactor 8 maps to zero.  The even ones,
+// ret[0], ret[2], ret[4] and ret[6], are Ristretto-equivalent to zero.
+//
+// The points are computed once; every call returns a copy.
+func TorsionPoints() [8]ExtendedPoint {
+	torsionPointsOnce.Do(func() {
+		torsionPoints[0].SetZero()
+		torsionPoints[1].setEdwards(&torsionGenerator)
+		for i := 2; i < 8; i++ {
+			torsionPoints[i].Add(&torsionPoints[i-1], &torsionPoints[1])
+		}
+	})
+	return torsionPoints
+}
+
+// Returns 1 if p and q are the same point on Edwards25519 and 0 otherwise.
+// Compares by cross-multiplication, without normalizing either point.
+//
+// Note that this is not Ristretto equality: points that differ by a point
+// of small order are considered different.
+func EqualsProjectiveExtendedI(p *ProjectivePoint, q *ExtendedPoint) int32 {
+	var l, r FieldElement
+	l.Mul(&p.X, &q.Z)
+	r.Mul(&q.X, &p.Z)
+	ret := l.EqualsI(&r)
+	l.Mul(&p.Y, &q.Z)
+	r.Mul(&q.Y, &p.Z)
+	return ret & l.EqualsI(&r)
+}
+
+// Returns 1 if p has small order, that is: if 8*p is zero, and 0 otherwise.
+func (p *ExtendedPoint) IsSmallOrderI() int32 {
+	var q ExtendedPoint
+	var t FieldElement
+	q.MulByCofactor(p)
+	t.sub(&q.Y, &q.Z)
+	return (1 - q.X.IsNonZeroI()) & (1 - t.IsNonZeroI())
+}
+
+// The inverse of the cofactor 8 modulo l, little endian.
+var invCofactor = [32]byte{
+	0x79, 0x2f, 0xdc, 0xe2, 0x29, 0xe5, 0x06, 0x61,
+	0xd0, 0xda, 0x1c, 0x7d, 0xb3, 0x9d, 0xd3, 0x07,
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x06,
+}
+
+// Returns the prime-order part of the sum of the points.
+//
+// The sum is multiplied by the cofactor 8, which removes any torsion
+// component, and then by the inverse of 8 modulo l, which undoes the
+// multiplication on the prime-order part.  Thus points that carry torsion,
+// for instance because they were supplied by an adversary, do not affect
+// the result beyond their prime-order part.
+func SafeSum(points []*ExtendedPoint) *ExtendedPoint {
+	var sum ExtendedPoint
+	sum.SetZero()
+	for _, q := range points {
+		sum.Add(&sum, q)
+	}
+	sum.MulByCofactor(&sum)
+	return new(ExtendedPoint).ScalarMult(&sum, &invCofactor)
+}
+
+// Returns a random generator H together with its discrete logarithm logH
+// with respect to the basepoint, that is: H = logH * B.  The randomness
+// is read from rng; if rng is nil, crypto/rand is used.
+//
+// Unlike nothing-up-my-sleeve generators such as those of
+// InnerProductGenerators(), the caller knows the discrete logarithm of H,
+// which is what protocols in which the prover owns H need.
+//
+// logH is drawn uniformly from [1, 2^252), which is statistically close to
+// uniform modulo l.
+func FreshGenerator(rng io.Reader) (H *ExtendedPoint, logH *[32]byte, err error) {
+	if rng == nil {
+		rng = rand.Reader
+	}
+	logH = new([32]byte)
+	for {
+		if _, err = io.ReadFull(rng, logH[:]); err != nil {
+			return nil, nil, err
+		}
+		logH[31] &= 15
+		if *logH != [32]byte{} {
+			break
+		}
+	}
+	return new(ExtendedPoint).ScalarMultBase(logH), logH, nil
+}
+
+// Sets p to a uniformly random point of the Ristretto group.  The
+// randomness is read from rng; if rng is nil, crypto/rand is used.
+// Returns p, or an error if reading from rng fails, in which case p is
+// left unchanged.
+//
+// p is the sum of the Elligator images of two random field elements,
+// instead of a random multiple of the basepoint: this is uniform over
+// the group, costs two Elligator maps instead of a scalar
+// multiplication, and nobody learns the discrete logarithm of p, so it
+// can also be used for blinding or as a generator.  Use FreshGenerator()
+// if the discrete logarithm is required.
+func (p *ExtendedPoint) Rand(rng io.Reader) (*ExtendedPoint, error) {
+	var buf [64]byte
+	if rng == nil {
+		rng = rand.Reader
+	}
+	if _, err := io.ReadFull(rng, buf[:]); err != nil {
+		return nil, err
+	}
+	return p.SetRistrettoHash(&buf), nil
+}
+
 // Pack p using the Ristretto encoding and return it.
 // Requires p to be even.
 func (p *ExtendedPoint) Ristretto() []byte {
@@ -275,11 +591,127 @@ func (p *ExtendedPoint) Ristretto() []byte {
 	return buf[:]
 }
 
+// Appends the Ristretto encoding of p to dst and returns the extended
+// slice.  Requires p to be even.
+func (p *ExtendedPoint) RistrettoAppend(dst []byte) []byte {
+	var buf [32]byte
+	p.RistrettoInto(&buf)
+	return append(dst, buf[:]...)
+}
+
+// Returns the Ristretto encoding of p with flag stored in the top bit,
+// which is always zero in a Ristretto encoding.  Requires p to be even.
+// Decode with SetRistrettoWithFlag().
+func (p *ExtendedPoint) RistrettoWithFlag(flag bool) [32]byte {
+	var buf [32]byte
+	p.RistrettoInto(&buf)
+	if flag {
+		buf[31] |= 0x80
+	}
+	return buf
+}
+
+// Sets p to the point encoded by buf as created by RistrettoWithFlag().
+// Returns whether the lower 255 bits of buf are the encoding of a group
+// element and the flag stored in the top bit.
+func (p *ExtendedPoint) SetRistrettoWithFlag(buf *[32]byte) (ok, flag bool) {
+	var enc [32]byte
+	copy(enc[:], buf[:])
+	flag = enc[31]>>7 == 1
+	enc[31] &= 0x7f
+	return p.SetRistretto(&enc), flag
+}
+
+// Implements encoding/BinaryMarshaler: returns the Ristretto encoding of p.
+// Requires p to be even.  Use RistrettoInto, if convenient, instead.
+func (p *ExtendedPoint) MarshalBinary() ([]byte, error) {
+	return p.Ristretto(), nil
+}
+
+// Implements encoding/BinaryUnmarshaler: sets p to the point with the
+// given Ristretto encoding.  Returns an error and leaves p unchanged if
+// data is not the canonical encoding of a group element.  Use
+// SetRistretto, if convenient, instead.
+func (p *ExtendedPoint) UnmarshalBinary(data []byte) error {
+	var buf [32]byte
+	var q ExtendedPoint
+	if len(data) != 32 {
+		return fmt.Errorf("Ristretto encoding should be 32 bytes; not %d",
+			len(data))
+	}
+	copy(buf[:], data)
+	if !q.SetRistretto(&buf) {
+		return fmt.Errorf("%x is not a valid Ristretto encoding", data)
+	}
+	p.Set(&q)
+	return nil
+}
+
+// Implements encoding/TextMarshaler: returns the Ristretto encoding of p
+// in lowercase hex.  Requires p to be even.
+func (p *ExtendedPoint) MarshalText() ([]byte, error) {
+	var buf [32]byte
+	p.RistrettoInto(&buf)
+	ret := make([]byte, hex.EncodedLen(32))
+	hex.Encode(ret, buf[:])
+	return ret, nil
+}
+
+// Implements encoding/TextUnmarshaler: sets p to the point of which the
+// Ristretto encoding is given in hex.  Returns an error and leaves p
+// unchanged if text is not the hex encoding of the canonical encoding of
+// a group element.
+func (p *ExtendedPoint) UnmarshalText(text []byte) error {
+	if len(text) != hex.EncodedLen(32) {
+		return fmt.Errorf("Ristretto encoding should be %d hex digits; not %d",
+			hex.EncodedLen(32), len(text))
+	}
+	buf := make([]byte, 32)
+	if _, err := hex.Decode(buf, text); err != nil {
+		return fmt.Errorf("Ristretto encoding is not valid hex: %v", err)
+	}
+	return p.UnmarshalBinary(buf)
+}
+
+// Implements encoding/json.Marshaler: encodes p as a JSON string holding
+// the hex Ristretto encoding of p, as MarshalText() does.  Requires p to
+// be even.
+//
+// As with the other methods, the receiver is a pointer: to marshal a
+// struct with an ExtendedPoint field, pass a pointer to the struct to
+// encoding/json, otherwise the field is not addressable and is encoded
+// coordinate by coordinate.
+func (p *ExtendedPoint) MarshalJSON() ([]byte, error) {
+	text, _ := p.MarshalText()
+	return json.Marshal(string(text))
+}
+
+// Implements encoding/json.Unmarshaler: parses a JSON string holding the
+// hex Ristretto encoding of a point, as UnmarshalText() does.  Returns an
+// error and leaves p unchanged on invalid input.
+//
+// A JSON null is rejected with an error rather than read as the identity,
+// so that a missing point is not silently accepted.  (A null for a field
+// of type *ExtendedPoint is handled by encoding/json itself, which sets
+// the pointer to nil.)
+func (p *ExtendedPoint) UnmarshalJSON(data []byte) error {
+	var text string
+	if string(data) == "null" {
+		return errors.New("Expected the hex Ristretto encoding of a point; got null")
+	}
+	if err := json.Unmarshal(data, &text); err != nil {
+		return fmt.Errorf("Expected the hex Ristretto encoding of a point: %v", err)
+	}
+	return p.UnmarshalText([]byte(text))
+}
+
 // Pack p using the Ristretto encoding and write to buf.  Returns p.
 // Requires p to be even.
+//
+// buf is only written after p has been read, so it is safe to decode
+// buf into p again with SetRistretto() afterwards.
 func (p *ExtendedPoint) RistrettoInto(buf *[32]byte) *ExtendedPoint {
-	var d, u1, u2, isr, i1, i2, zInv, denInv, nx, ny, s FieldElement
-	var b int32
+	var d, u1, u2, isr FieldElement
 
 	d.add(&p.Z, &p.Y)
 	u1.sub(&p.Z, &p.Y)
@@ -291,8 +723,24 @@ func (p *ExtendedPoint) RistrettoInto(buf *[32]byte) *ExtendedPoint {
 	isr.Mul(&isr, &u1)
 	isr.InvSqrt(&isr)
 
-	i1.Mul(&isr, &u1)
-	i2.Mul(&isr, &u2)
+	return p.ristrettoIntoIsr(buf, &isr)
+}
+
+// Pack p using the Ristretto encoding and write to buf, given
+// isr = ±1/sqrt((Z^2 - Y^2) (XY)^2).  Returns p.  Requires p to be even.
+func (p *ExtendedPoint) ristrettoIntoIsr(buf *[32]byte,
+	isr *FieldElement) *ExtendedPoint {
+	var d, u1, u2, i1, i2, zInv, denInv, nx, ny, s FieldElement
+	var b int32
+
+	d.add(&p.Z, &p.Y)
+	u1.sub(&p.Z, &p.Y)
+	u1.Mul(&u1, &d)
+
+	u2.Mul(&p.X, &p.Y)
+
+	i1.Mul(isr, &u1)
+	i2.Mul(isr, &u2)
 
 	zInv.Mul(&i1, &i2)
 	zInv.Mul(&zInv, &p.T)
@@ -309,63 +757,240 @@ func (p *ExtendedPoint) RistrettoInto(buf *[32]byte) *ExtendedPoint {
 	denInv.ConditionalSet(&i2, b)
 
 	d.Mul(&nx, &zInv)
-	b = d.IsNegativeI()
-	d.Neg(&ny)
-	ny.ConditionalSet(&d, b)
+	ny.ConditionalNegate(d.IsNegativeI())
 
 	s.sub(&p.Z, &ny)
 	s.Mul(&s, &denInv)
 
-	b = s.IsNegativeI()
-	d.Neg(&s)
-	s.ConditionalSet(&d, b)
+	s.ConditionalNegate(s.IsNegativeI())
 
 	s.BytesInto(buf)
 	return p
 }
 
+// Returns the Ristretto encodings of a and b concatenated, as for an
+// ElGamal ciphertext.  Requires a and b to be even.
+func EncodePair(a, b *ExtendedPoint) [64]byte {
+	var ret [64]byte
+	var buf [32]byte
+	a.RistrettoInto(&buf)
+	copy(ret[:32], buf[:])
+	b.RistrettoInto(&buf)
+	copy(ret[32:], buf[:])
+	return ret
+}
+
+// Decodes a pair of points encoded with EncodePair().  Returns whether
+// both halves of buf encode a point.
+func DecodePair(buf *[64]byte) (a, b ExtendedPoint, ok bool) {
+	var half [32]byte
+	copy(half[:], buf[:32])
+	okA := a.SetRistretto(&half)
+	copy(half[:], buf[32:])
+	okB := b.SetRistretto(&half)
+	return a, b, okA && okB
+}
+
+// Returns the Ristretto encoding of -P, where P is the point encoded in
+// buf.  Returns false if buf does not encode a point.
+//
+// The encoding of -P is not a simple function of that of P: the encoding
+// of P with its sign flipped decodes to P again, and is therefore rejected.
+// Instead, this decodes P, negates it and encodes -P again, but computes
+// the inverse square root needed for the encoding together with the one
+// needed for decoding.  This makes it almost twice as fast as decoding
+// with SetRistretto() and encoding with RistrettoInto().
+func RistrettoNegateEncoding(buf *[32]byte) ([32]byte, bool) {
+	var p ExtendedPoint
+	var isr FieldElement
+	var ret [32]byte
+	if decodeRistretto(buf, &p.X, &p.Y, &p.T, &isr) != 0 {
+		return ret, false
+	}
+	p.Z.SetOne()
+	p.Neg(&p).ristrettoIntoIsr(&ret, &isr)
+	return ret, true
+}
+
+// Returns the Ristretto encoding of cur - prev.  Together with
+// ApplyDelta(), this stores a sequence of points as the differences of
+// consecutive points, which repeat if the points advance by a fixed step.
+// Requires prev and cur to be even.
+func EncodeDelta(prev, cur *ExtendedPoint) [32]byte {
+	var d ExtendedPoint
+	var ret [32]byte
+	d.Sub(cur, prev).RistrettoInto(&ret)
+	return ret
+}
+
+// Returns prev plus the point encoded in delta, which undoes
+// EncodeDelta().  Returns false if delta does not encode a point.
+func ApplyDelta(prev *ExtendedPoint, delta *[32]byte) (*ExtendedPoint, bool) {
+	var d ExtendedPoint
+	if !d.SetRistretto(delta) {
+		return nil, false
+	}
+	return d.Add(prev, &d), true
+}
+
+// Returns an error if buf is not the canonical Ristretto encoding of a
+// group element: either it does not decode, or decoding and encoding it
+// again does not give back buf.  The latter can not happen for a correct
+// implementation; this is meant for property tests and fuzzers.
+func AssertCanonical(buf *[32]byte) error {
+	var p ExtendedPoint
+	var buf2 [32]byte
+	if !p.SetRistretto(buf) {
+		return fmt.Errorf("%x is not a valid Ristretto encoding", buf[:])
+	}
+	p.RistrettoInto(&buf2)
+	if buf2 != *buf {
+		return fmt.Errorf("%x decodes, but re-encodes to %x", buf[:], buf2[:])
+	}
+	return nil
+}
+
+// Writes the u-coordinate u = (1+y)/(1-y) of the point on the birationally
+// equivalent Montgomery curve v^2 = u^3 + 486662 u^2 + u to buf.  This is
+// the input expected by an X25519 scalar multiplication; the standard
+// Ed25519 basepoint maps to u = 9.  The neutral element, which has no
+// image, is mapped to u = 0.
+// Returns p.
+//
+// The u-coordinate is a function of the Edwards point p and not of the
+// Ristretto point it represents: two Ristretto-equal points generally
+// have different u-coordinates, as they differ by a point of small order.
+// X25519 clamps its scalar to a multiple of the cofactor 8, which clears
+// this difference, so its output does only depend on the Ristretto point.
+func (p *ExtendedPoint) MontgomeryUInto(buf *[32]byte) *ExtendedPoint {
+	var num, den FieldElement
+	num.add(&p.Z, &p.Y)
+	den.sub(&p.Z, &p.Y)
+	den.Inverse(&den)
+	num.Mul(&num, &den)
+	num.BytesInto(buf)
+	return p
+}
+
+// Returns the u-coordinate of p in the form expected by X25519.
+// See MontgomeryUInto().
+func (p *ExtendedPoint) MontgomeryLadderInput() [32]byte {
+	var buf [32]byte
+	p.MontgomeryUInto(&buf)
+	return buf
+}
+
 // Compute 5-bit window for the scalar s.
 func computeScalarWindow5(s *[32]byte, w *[51]int8) {
-	for i := 0; i < 6; i++ {
-		w[8*i+0] = int8(s[5*i+0] & 31)
-		w[8*i+1] = int8((s[5*i+0] >> 5) & 31)
-		w[8*i+1] ^= int8((s[5*i+1] << 3) & 31)
-		w[8*i+2] = int8((s[5*i+1] >> 2) & 31)
-		w[8*i+3] = int8((s[5*i+1] >> 7) & 31)
-		w[8*i+3] ^= int8((s[5*i+2] << 1) & 31)
-		w[8*i+4] = int8((s[5*i+2] >> 4) & 31)
-		w[8*i+4] ^= int8((s[5*i+3] << 4) & 31)
-		w[8*i+5] = int8((s[5*i+3] >> 1) & 31)
-		w[8*i+6] = int8((s[5*i+3] >> 6) & 31)
-		w[8*i+6] ^= int8((s[5*i+4] << 2) & 31)
-		w[8*i+7] = int8((s[5*i+4] >> 3) & 31)
+	RecodeScalarWindow(s, 5, w[:])
+}
+
+// Computes the signed radix 2^width representation of s and stores it
+// into out.  Afterwards s = sum_i out[i] * 2^(width*i), where every digit
+// except the last is in [-2^(width-1), 2^(width-1)).  Requires
+// 2 <= width <= 7.
+//
+// Bits of s beyond width*len(out) are ignored, so out should have at
+// least ceil(256/width) entries unless s is known to be smaller.  The last
+// digit absorbs the final carry: if s < 2^253, as is the case for reduced
+// scalars, then it is at most 2^(width-1) for the lengths above.
+// ScalarMult() uses width 5 with 51 digits.
+//
+// The running time does not depend on s.
+func RecodeScalarWindow(s *[32]byte, width uint, out []int8) {
+	if width < 2 || width > 7 {
+		panic("edwards25519: RecodeScalarWindow: width must be between 2 and 7")
+	}
+	if len(out) == 0 {
+		return
+	}
+
+	mask := uint16(1)<<width - 1
+	for i := range out {
+		pos := uint(i) * width
+		idx := pos / 8
+		var buf uint16
+		if idx < 32 {
+			buf = uint16(s[idx])
+		}
+		if idx+1 < 32 {
+			buf |= uint16(s[idx+1]) << 8
+		}
+		out[i] = int8((buf >> (pos % 8)) & mask)
 	}
-	w[8*6+0] = int8(s[5*6+0] & 31)
-	w[8*6+1] = int8((s[5*6+0] >> 5) & 31)
-	w[8*6+1] ^= int8((s[5*6+1] << 3) & 31)
-	w[8*6+2] = int8((s[5*6+1] >> 2) & 31)
 
 	/* Making it signed */
-	var carry int8 = 0
-	for i := 0; i < 50; i++ {
-		w[i] += carry
-		w[i+1] += w[i] >> 5
-		w[i] &= 31
-		carry = w[i] >> 4
-		w[i] -= carry << 5
+	half := int32(1) << (width - 1)
+	carry := int32(0)
+	for i := 0; i < len(out)-1; i++ {
+		d := int32(out[i]) + carry
+		carry = (d + half) >> width
+		out[i] = int8(d - (carry << width))
 	}
-	w[50] += carry
+	out[len(out)-1] += int8(carry)
+}
+
+// Reusable buffers for ScalarMultInto().
+type ScalarMultScratch struct {
+	lut    [17]ExtendedPoint
+	window [51]int8
 }
 
 // Set p to s * q.  Returns p.
+//
+// The running time and memory access pattern do not depend on s (nor on
+// q), so s may be secret.  See ScalarMultInto() for details.
 func (p *ExtendedPoint) ScalarMult(q *ExtendedPoint, s *[32]byte) *ExtendedPoint {
+	var scratch ScalarMultScratch
+	return ScalarMultInto(p, q, s, &scratch)
+}
+
+// Sets out to s * q using the buffers in scratch, which may be reused
+// between calls to avoid setting up a fresh lookup table each time.
+// Returns out.
+//
+// The digits of s are cleared from scratch before returning, but the
+// lookup table of multiples of q is left behind.  If q is secret, do not
+// share or leak scratch.  A scratch must not be used by two calls at once.
+//
+// Like ScalarMult(), this is constant-time in s: there are no branches
+// on, and no memory accesses indexed by, data derived from s.
+func ScalarMultInto(out, q *ExtendedPoint, s *[32]byte,
+	scratch *ScalarMultScratch) *ExtendedPoint {
 	// See eg. https://cryptojedi.org/peter/data/eccss-20130911b.pdf
-	var lut [17]ExtendedPoint
+	//
+	// Constant-time audit notes:
+	//  - RecodeScalarWindow() only branches on the digit index; the
+	//    carries are propagated with shifts.
+	//  - The table is built from q alone and every entry is touched for
+	//    every digit: the lookup below uses equal15() and ConditionalSet()
+	//    instead of indexing lut by the digit.
+	//  - The sign of the digit is applied with negative() and
+	//    ConditionalNegate() instead of a branch.
+	//  - The loop always runs 51 times, also for leading zero digits, and
+	//    Add() and DoubleN() are branch-free field arithmetic.
 	var t ExtendedPoint
-	var window [51]int8
+	lut := &scratch.lut
+	window := &scratch.window
 
 	// Precomputations.
-	computeScalarWindow5(s, &window)
+	computeScalarWindow5(s, window)
+	precomputeWindowTable(q, lut)
+
+	// Compute!
+	out.SetZero()
+	for i := 50; i >= 0; i-- {
+		out.DoubleN(out, 5)
+		out.Add(out, t.selectWindow(lut, int32(window[i])))
+	}
+
+	// Don't leave the digits of s behind in scratch.
+	*window = [51]int8{}
+	return out
+}
+
+// Sets lut[i] to i*q for 0 <= i <= 16.
+func precomputeWindowTable(q *ExtendedPoint, lut *[17]ExtendedPoint) {
 	lut[0].SetZero()
 	lut[1].Set(q)
 	for i := 2; i < 16; i += 2 {
@@ -373,35 +998,35 @@ func (p *ExtendedPoint) ScalarMult(q *ExtendedPoint, s *[32]byte) *ExtendedPoint
 		lut[i+1].Add(&lut[i], q)
 	}
 	lut[16].Double(&lut[8])
+}
 
-	// Compute!
-	p.SetZero()
-	for i := 50; i >= 0; i-- {
-		var pp ProjectivePoint
-		var cp CompletedPoint
-		cp.DoubleExtended(p)
-		for z := 0; z < 4; z++ {
-			pp.SetCompleted(&cp)
-			cp.DoubleProjective(&pp)
-		}
-		p.SetCompleted(&cp)
-
-		t.Set(&lut[0])
-		b := int32(window[i])
-		for j := 1; j <= 16; j++ {
-			c := equal15(b, int32(-j)) | equal15(b, int32(j))
-			t.ConditionalSet(&lut[j], c)
-		}
-		var v FieldElement
-		c := negative(b)
-		v.Neg(&t.X)
-		t.X.ConditionalSet(&v, c)
-		v.Neg(&t.T)
-		t.T.ConditionalSet(&v, c)
-
-		p.Add(p, &t)
+// Sets p to b*q in constant time, where lut is the table of q computed by
+// precomputeWindowTable() and -16 <= b <= 16.  Returns p.
+func (p *ExtendedPoint) selectWindow(lut *[17]ExtendedPoint, b int32) *ExtendedPoint {
+	p.Set(&lut[0])
+	for j := 1; j <= 16; j++ {
+		c := equal15(b, int32(-j)) | equal15(b, int32(j))
+		p.ConditionalSet(&lut[j], c)
 	}
+	return p.ConditionalNegate(negative(b))
+}
 
+// Set p to s * q.  Returns p.
+//
+// This is a constant-time double-and-add over the 256 bits of s that adds
+// either q or zero for every bit with ConditionalAdd(), so that, next to
+// p, it keeps only a copy of q and one temporary point on the stack.
+// ScalarMult() instead keeps a table of 17 points, about 2.7kB, and needs
+// one addition for every five bits.  This makes ScalarMultLowMem() about
+// one and a half times slower; use it only where memory is very tight.
+func (p *ExtendedPoint) ScalarMultLowMem(q *ExtendedPoint, s *[32]byte) *ExtendedPoint {
+	var base ExtendedPoint
+	base.Set(q)
+	p.SetZero()
+	for i := 255; i >= 0; i-- {
+		p.Double(p)
+		p.ConditionalAdd(&base, int32(s[i/8]>>uint(i%8))&1)
+	}
 	return p
 }
 
@@ -425,6 +1050,49 @@ func (p *ExtendedPoint) RistrettoEqualsI(q *ExtendedPoint) int32 {
 	return 1 - ((1 - x1y2.EqualsI(&x2y1)) & (1 - x1x2.EqualsI(&y1y2)))
 }
 
+// Returns whether p and q are in the same Ristretto equivalence class.
+// Assumes p and q are both even.  Like RistrettoEqualsI(), this is
+// constant-time.
+func (p *ExtendedPoint) Equals(q *ExtendedPoint) bool {
+	return p.RistrettoEqualsI(q) == 1
+}
+
+// Sets p to the canonical representative of its Ristretto equivalence
+// class: the point obtained by decoding its own encoding, with every
+// coordinate fully reduced.  Afterwards, any two points that are
+// Ristretto-equivalent have identical coordinates, so they can be
+// compared with == or used as map keys.  Assumes p is even.  Returns p.
+func (p *ExtendedPoint) Canonicalize() *ExtendedPoint {
+	var buf [32]byte
+	p.RistrettoInto(&buf)
+	p.SetRistretto(&buf)
+
+	// SetRistretto sets Z to one, but the other coordinates may still
+	// be represented by limbs that are not fully reduced.
+	p.X.BytesInto(&buf)
+	p.X.SetBytes(&buf)
+	p.Y.BytesInto(&buf)
+	p.Y.SetBytes(&buf)
+	p.T.BytesInto(&buf)
+	p.T.SetBytes(&buf)
+	return p
+}
+
+// Returns 1 if p is Ristretto-equivalent to zero and 0 otherwise.
+// Assumes p is even.
+//
+// This is RistrettoEqualsI() against zero with the products worked out:
+// the even points of small order are exactly those with X = 0 or Y = 0.
+func (p *ExtendedPoint) IsIdentityI() int32 {
+	return 1 - (p.X.IsNonZeroI() & p.Y.IsNonZeroI())
+}
+
+// Returns whether p is Ristretto-equivalent to zero.  Assumes p is even.
+// Like IsIdentityI(), this is constant-time.
+func (p *ExtendedPoint) IsIdentity() bool {
+	return p.IsIdentityI() == 1
+}
+
 // WARNING This operation is not constant-time.  Do not use for cryptography
 //         unless you're sure this is not an issue.
 func (p *ExtendedPoint) String() string {
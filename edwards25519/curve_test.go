@@ -2,8 +2,14 @@ package edwards25519_test
 
 import (
 	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"io"
 	"math/big"
+	"os"
+	"sort"
 	"testing"
+	"time"
 
 	"github.com/bwesterb/go-ristretto/cref"
 	"github.com/bwesterb/go-ristretto/edwards25519"
@@ -109,6 +115,34 @@ func TestPointAdd(t *testing.T) {
 	}
 }
 
+func TestAddExtendedChaining(t *testing.T) {
+	var buf [32]byte
+	var fe edwards25519.FieldElement
+	var cp edwards25519.CompletedPoint
+	var pp edwards25519.ProjectivePoint
+	var p, q, want, got edwards25519.ExtendedPoint
+	for i := 0; i < 100; i++ {
+		rnd.Read(buf[:])
+		fe.SetBytes(&buf)
+		p.SetCompleted(cp.SetRistrettoElligator2(&fe))
+		rnd.Read(buf[:])
+		fe.SetBytes(&buf)
+		q.SetCompleted(cp.SetRistrettoElligator2(&fe))
+
+		want.Add(&p, &q)
+		want.Double(&want)
+
+		cp.AddExtended(&p, &q)
+		pp.SetCompleted(&cp)
+		cp.DoubleProjective(&pp)
+		got.SetCompleted(&cp)
+
+		if got.RistrettoEqualsI(&want) != 1 {
+			t.Fatalf("2(p+q) via CompletedPoint %v != %v", got, want)
+		}
+	}
+}
+
 func TestScalarMult(t *testing.T) {
 	var buf, sBuf, cBuf, goBuf [32]byte
 	var biS big.Int
@@ -146,6 +180,86 @@ func TestScalarMult(t *testing.T) {
 	}
 }
 
+func TestRecodeScalarWindow(t *testing.T) {
+	var s, rs [32]byte
+	var sbi, wbi, power, summand big.Int
+	for width := uint(2); width <= 7; width++ {
+		out := make([]int8, (256+width-1)/width)
+		half := int8(1) << (width - 1)
+		for i := 0; i < 100; i++ {
+			rnd.Read(s[:])
+			s[31] &= 15
+			edwards25519.RecodeScalarWindow(&s, width, out)
+			for j := 0; j < 32; j++ {
+				rs[j] = s[31-j]
+			}
+			sbi.SetBytes(rs[:])
+			power.SetUint64(1)
+			wbi.SetUint64(0)
+			for j := 0; j < len(out); j++ {
+				if j < len(out)-1 && (out[j] < -half || out[j] >= half) {
+					t.Fatalf("width %d: digit %d out of range: %v", width, j, out)
+				}
+				summand.SetInt64(int64(out[j]))
+				summand.Mul(&summand, &power)
+				wbi.Add(&wbi, &summand)
+				power.Lsh(&power, width)
+			}
+			if wbi.Cmp(&sbi) != 0 {
+				t.Fatalf("width %d: Recode(%v) = %v  %v != %v",
+					width, s, out, &sbi, &wbi)
+			}
+		}
+	}
+}
+
+func TestCanonicalize(t *testing.T) {
+	var p, q, r edwards25519.ExtendedPoint
+	var torsion edwards25519.ExtendedPoint
+	var fe edwards25519.FieldElement
+	var cp edwards25519.CompletedPoint
+	var buf [32]byte
+	for i := 0; i < 1000; i++ {
+		rnd.Read(buf[:])
+		fe.SetBytes(&buf)
+		cp.SetRistrettoElligator2(&fe)
+		p.SetCompleted(&cp)
+
+		// q = p + torsion and r = (p + p) - p are group-equal to p,
+		// but have different coordinates.
+		switch i % 3 {
+		case 0:
+			torsion.SetTorsion1()
+		case 1:
+			torsion.SetTorsion2()
+		case 2:
+			torsion.SetTorsion3()
+		}
+		q.Add(&p, &torsion)
+		r.Double(&p)
+		r.Sub(&r, &p)
+		if p == q || p == r {
+			t.Fatalf("%v: test points already coordinate-equal", p)
+		}
+
+		p.Canonicalize()
+		q.Canonicalize()
+		r.Canonicalize()
+		if p != q || p != r {
+			t.Fatalf("Canonicalize: %v, %v, %v differ", p, q, r)
+		}
+		if !p.Equals(&q) {
+			t.Fatalf("Canonicalize changed the group element")
+		}
+	}
+
+	p.SetZero()
+	q.SetTorsion2()
+	if *p.Canonicalize() != *q.Canonicalize() {
+		t.Fatalf("Canonicalize: zero %v != %v", p, q)
+	}
+}
+
 func TestRistrettoEqualsI(t *testing.T) {
 	var ep1, ep2 edwards25519.ExtendedPoint
 	var torsion [4]edwards25519.ExtendedPoint
@@ -166,6 +280,327 @@ func TestRistrettoEqualsI(t *testing.T) {
 			if ep1.RistrettoEqualsI(&ep2) != 1 {
 				t.Fatalf("%v + %v != %v", ep1, torsion[j], ep2)
 			}
+			if !ep1.Equals(&ep2) {
+				t.Fatalf("Equals(%v, %v + %v) is false", ep1, ep1, torsion[j])
+			}
+		}
+		ep2.Double(&ep1)
+		if ep1.Equals(&ep2) || ep1.RistrettoEqualsI(&ep2) != 0 {
+			t.Fatalf("%v equals its double", ep1)
+		}
+	}
+}
+
+func TestClone(t *testing.T) {
+	var p edwards25519.ExtendedPoint
+	var pp edwards25519.ProjectivePoint
+	var fe edwards25519.FieldElement
+	var cp edwards25519.CompletedPoint
+	var buf [32]byte
+	rnd.Read(buf[:])
+	fe.SetBytes(&buf)
+	cp.SetRistrettoElligator2(&fe)
+	p.SetCompleted(&cp)
+	pp.SetExtended(&p)
+
+	q := p.Clone()
+	if q == &p || *q != p {
+		t.Fatalf("ExtendedPoint.Clone() = %v != %v", q, &p)
+	}
+	q.Double(q)
+	if q.Equals(&p) {
+		t.Fatalf("ExtendedPoint.Clone() shares memory with the original")
+	}
+
+	qq := pp.Clone()
+	if qq == &pp || *qq != pp {
+		t.Fatalf("ProjectivePoint.Clone() differs")
+	}
+
+	cq := cp.Clone()
+	if cq == &cp || *cq != cp {
+		t.Fatalf("CompletedPoint.Clone() differs")
+	}
+}
+
+func TestExtendedPointConditionalNegate(t *testing.T) {
+	var p, q, neg edwards25519.ExtendedPoint
+	var fe edwards25519.FieldElement
+	var cp edwards25519.CompletedPoint
+	var buf [32]byte
+	for i := 0; i < 100; i++ {
+		rnd.Read(buf[:])
+		fe.SetBytes(&buf)
+		p.SetCompleted(cp.SetRistrettoElligator2(&fe))
+		neg.Neg(&p)
+
+		q.Set(&p)
+		if q.ConditionalNegate(0) != &q || q != p {
+			t.Fatalf("ConditionalNegate(0) changed %v into %v", &p, &q)
+		}
+		if !q.ConditionalNegate(1).Equals(&neg) {
+			t.Fatalf("ConditionalNegate(1) of %v = %v != %v", &p, &q, &neg)
+		}
+	}
+}
+
+func TestIsIdentityI(t *testing.T) {
+	var p, zero edwards25519.ExtendedPoint
+	var fe edwards25519.FieldElement
+	var cp edwards25519.CompletedPoint
+	var buf [32]byte
+	for i, q := range []*edwards25519.ExtendedPoint{
+		zero.SetZero(),
+		new(edwards25519.ExtendedPoint).SetTorsion1(),
+		new(edwards25519.ExtendedPoint).SetTorsion2(),
+		new(edwards25519.ExtendedPoint).SetTorsion3(),
+	} {
+		if q.IsIdentityI() != 1 || !q.IsIdentity() {
+			t.Fatalf("Torsion point %d is not the identity", i)
+		}
+	}
+	for i := 0; i < 1000; i++ {
+		rnd.Read(buf[:])
+		fe.SetBytes(&buf)
+		p.SetCompleted(cp.SetRistrettoElligator2(&fe))
+		if p.IsIdentityI() != p.RistrettoEqualsI(&zero) {
+			t.Fatalf("IsIdentityI(%v) = %d", &p, p.IsIdentityI())
+		}
+		if p.IsIdentity() {
+			t.Fatalf("%v is the identity", &p)
+		}
+	}
+}
+
+func TestConditionalAdd(t *testing.T) {
+	var p, q, orig, sum edwards25519.ExtendedPoint
+	var fe edwards25519.FieldElement
+	var cp edwards25519.CompletedPoint
+	var buf [32]byte
+	for i := 0; i < 100; i++ {
+		rnd.Read(buf[:])
+		fe.SetBytes(&buf)
+		orig.SetCompleted(cp.SetRistrettoElligator2(&fe))
+		rnd.Read(buf[:])
+		fe.SetBytes(&buf)
+		q.SetCompleted(cp.SetRistrettoElligator2(&fe))
+		sum.Add(&orig, &q)
+
+		p.Set(&orig)
+		if !p.ConditionalAdd(&q, 1).Equals(&sum) {
+			t.Fatalf("ConditionalAdd(%v, %v, 1) != sum", &orig, &q)
+		}
+		p.Set(&orig)
+		if edwardsEncoding(p.ConditionalAdd(&q, 0)) != edwardsEncoding(&orig) {
+			t.Fatalf("ConditionalAdd(%v, %v, 0) changes the point", &orig, &q)
+		}
+	}
+}
+
+func TestExtendedPointBinaryMarshaling(t *testing.T) {
+	var p, q edwards25519.ExtendedPoint
+	var fe edwards25519.FieldElement
+	var cp edwards25519.CompletedPoint
+	var buf [32]byte
+	for i := 0; i < 100; i++ {
+		rnd.Read(buf[:])
+		fe.SetBytes(&buf)
+		p.SetCompleted(cp.SetRistrettoElligator2(&fe))
+		data, err := p.MarshalBinary()
+		if err != nil {
+			t.Fatalf("MarshalBinary: %v", err)
+		}
+		if err := q.UnmarshalBinary(data); err != nil {
+			t.Fatalf("UnmarshalBinary: %v", err)
+		}
+		if !p.Equals(&q) {
+			t.Fatalf("UnmarshalBinary(MarshalBinary(%v)) = %v", &p, &q)
+		}
+	}
+
+	// Wrong lengths and invalid encodings are rejected
+	q.SetBase()
+	data, _ := p.MarshalBinary()
+	if q.UnmarshalBinary(data[:31]) == nil ||
+		q.UnmarshalBinary(append(data, 0)) == nil {
+		t.Fatalf("UnmarshalBinary accepts the wrong length")
+	}
+	data[0] |= 1 // negative field elements are not canonical
+	if q.UnmarshalBinary(data) == nil {
+		t.Fatalf("UnmarshalBinary accepts a negative s")
+	}
+	if !q.Equals(new(edwards25519.ExtendedPoint).SetBase()) {
+		t.Fatalf("UnmarshalBinary changed the point on failure")
+	}
+}
+
+func TestFreshGenerator(t *testing.T) {
+	var B, want edwards25519.ExtendedPoint
+	B.SetBase()
+	for i := 0; i < 20; i++ {
+		var rng io.Reader = rnd
+		if i%2 == 0 {
+			rng = nil
+		}
+		H, logH, err := edwards25519.FreshGenerator(rng)
+		if err != nil {
+			t.Fatalf("FreshGenerator: %v", err)
+		}
+		if !H.Equals(want.ScalarMult(&B, logH)) {
+			t.Fatalf("FreshGenerator() = %v != [%x]B", H, logH)
+		}
+		if H.IsIdentity() {
+			t.Fatalf("FreshGenerator returned the identity")
+		}
+	}
+	if _, _, err := edwards25519.FreshGenerator(bytes.NewReader(nil)); err == nil {
+		t.Fatalf("FreshGenerator ignores a failing reader")
+	}
+}
+
+func TestExtendedPointRand(t *testing.T) {
+	var p, q, want edwards25519.ExtendedPoint
+	var fe edwards25519.FieldElement
+	var cp edwards25519.CompletedPoint
+	var buf [64]byte
+	var half [32]byte
+	for i := 0; i < 20; i++ {
+		rnd.Read(buf[:])
+		if _, err := p.Rand(bytes.NewReader(buf[:])); err != nil {
+			t.Fatalf("Rand: %v", err)
+		}
+		copy(half[:], buf[:32])
+		want.SetCompleted(cp.SetRistrettoElligator2(fe.SetBytes(&half)))
+		copy(half[:], buf[32:])
+		q.SetCompleted(cp.SetRistrettoElligator2(fe.SetBytes(&half)))
+		want.Add(&want, &q)
+		if !p.Equals(&want) {
+			t.Fatalf("Rand() = %v != %v", p, want)
+		}
+
+		if _, err := q.Rand(nil); err != nil {
+			t.Fatalf("Rand(nil): %v", err)
+		}
+		if q.Equals(&p) || q.IsIdentity() {
+			t.Fatalf("Rand(nil) = %v is not random", q)
+		}
+	}
+
+	want.Set(&p)
+	if _, err := p.Rand(bytes.NewReader(buf[:63])); err == nil {
+		t.Fatalf("Rand ignores a short read")
+	}
+	if p != want {
+		t.Fatalf("Rand changed p on error")
+	}
+}
+
+func TestExtendedPointTextMarshaling(t *testing.T) {
+	var s, s2 struct {
+		P edwards25519.ExtendedPoint
+	}
+	var fe edwards25519.FieldElement
+	var cp edwards25519.CompletedPoint
+	var buf [32]byte
+	for i := 0; i < 100; i++ {
+		rnd.Read(buf[:])
+		fe.SetBytes(&buf)
+		s.P.SetCompleted(cp.SetRistrettoElligator2(&fe))
+		data, err := json.Marshal(&s)
+		if err != nil {
+			t.Fatalf("json.Marshal: %v", err)
+		}
+		s.P.RistrettoInto(&buf)
+		if string(data) != `{"P":"`+hex.EncodeToString(buf[:])+`"}` {
+			t.Fatalf("json.Marshal() = %s", data)
+		}
+		if err := json.Unmarshal(data, &s2); err != nil {
+			t.Fatalf("json.Unmarshal: %v", err)
+		}
+		if !s.P.Equals(&s2.P) {
+			t.Fatalf("Text round trip of %v gives %v", &s.P, &s2.P)
+		}
+	}
+
+	text, _ := s.P.MarshalText()
+	bad := append([]byte(nil), text...)
+	bad[5] = 'x'
+	s.P.RistrettoInto(&buf)
+	buf[0] |= 1 // negative field elements are not canonical
+	negative := []byte(hex.EncodeToString(buf[:]))
+	for _, in := range [][]byte{text[:62], append(text, '0'), bad, negative} {
+		if s2.P.UnmarshalText(in) == nil {
+			t.Fatalf("UnmarshalText(%s) succeeds", in)
+		}
+	}
+}
+
+func TestExtendedPointJSON(t *testing.T) {
+	type message struct {
+		Name string
+		P    edwards25519.ExtendedPoint
+		Q    *edwards25519.ExtendedPoint
+	}
+	var in, out message
+	var fe edwards25519.FieldElement
+	var cp edwards25519.CompletedPoint
+	var buf [32]byte
+	rnd.Read(buf[:])
+	fe.SetBytes(&buf)
+	in.Name = "test"
+	in.P.SetCompleted(cp.SetRistrettoElligator2(&fe))
+	in.Q = new(edwards25519.ExtendedPoint).SetBase()
+
+	data, err := json.Marshal(&in)
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatalf("json.Unmarshal(%s): %v", data, err)
+	}
+	if out.Name != in.Name || !out.P.Equals(&in.P) || !out.Q.Equals(in.Q) {
+		t.Fatalf("JSON round trip of %+v gives %+v", in, out)
+	}
+
+	// null is an error, except for a pointer field
+	if err := json.Unmarshal([]byte(`{"P":null}`), &out); err == nil {
+		t.Fatalf("json.Unmarshal accepts null for an ExtendedPoint")
+	}
+	if err := json.Unmarshal([]byte(`{"Q":null}`), &out); err != nil || out.Q != nil {
+		t.Fatalf("json.Unmarshal does not set a null *ExtendedPoint to nil")
+	}
+	for _, bad := range []string{`{"P":5}`, `{"P":"00"}`, `{"P":"zz"}`} {
+		if err := json.Unmarshal([]byte(bad), &out); err == nil {
+			t.Fatalf("json.Unmarshal(%s) succeeds", bad)
+		}
+	}
+}
+
+func TestRistrettoWithFlag(t *testing.T) {
+	var p, q edwards25519.ExtendedPoint
+	var fe edwards25519.FieldElement
+	var cp edwards25519.CompletedPoint
+	var buf [32]byte
+	for i := 0; i < 100; i++ {
+		rnd.Read(buf[:])
+		fe.SetBytes(&buf)
+		p.SetCompleted(cp.SetRistrettoElligator2(&fe))
+		p.RistrettoInto(&buf)
+		for _, flag := range []bool{false, true} {
+			enc := p.RistrettoWithFlag(flag)
+			if flag == (enc == buf) {
+				t.Fatalf("RistrettoWithFlag(%v) = %x for %x", flag, enc, buf)
+			}
+			ok, flag2 := q.SetRistrettoWithFlag(&enc)
+			if !ok || flag2 != flag || !q.Equals(&p) {
+				t.Fatalf("SetRistrettoWithFlag(%x) = %v, %v", enc, ok, flag2)
+			}
+
+			// Invalid point bits are rejected regardless of the flag
+			enc[0] |= 1
+			if ok, _ := q.SetRistrettoWithFlag(&enc); ok {
+				t.Fatalf("SetRistrettoWithFlag(%x) accepts a negative s", enc)
+			}
 		}
 	}
 }
@@ -201,6 +636,176 @@ func BenchmarkRistrettoUnpack(b *testing.B) {
 	}
 }
 
+func randomRistrettoEncodings(n int) [][32]byte {
+	var fe edwards25519.FieldElement
+	var cp edwards25519.CompletedPoint
+	var ep edwards25519.ExtendedPoint
+	bufs := make([][32]byte, n)
+	for i := range bufs {
+		rnd.Read(bufs[i][:])
+		fe.SetBytes(&bufs[i])
+		ep.SetCompleted(cp.SetRistrettoElligator2(&fe))
+		ep.RistrettoInto(&bufs[i])
+	}
+	return bufs
+}
+
+func TestIsValidRistrettoEncoding(t *testing.T) {
+	var p edwards25519.ExtendedPoint
+	check := func(buf *[32]byte, class string) {
+		want := p.SetRistretto(buf)
+		if got := edwards25519.IsValidRistrettoEncoding(buf); got != want {
+			t.Fatalf("IsValidRistrettoEncoding(%x) = %v != %v (%s)",
+				buf, got, want, class)
+		}
+	}
+
+	bufs := randomRistrettoEncodings(100)
+	for i := range bufs {
+		check(&bufs[i], "valid")
+		bufs[i][0] |= 1
+		check(&bufs[i], "negative s")
+		bufs[i][0] &^= 1
+		bufs[i][31] |= 0x80
+		check(&bufs[i], "high bit set")
+	}
+
+	// s = p is a non-canonical encoding of zero
+	var buf [32]byte
+	for i := 0; i < 32; i++ {
+		buf[i] = 0xff
+	}
+	buf[0] = 0xed
+	buf[31] = 0x7f
+	check(&buf, "non-canonical")
+
+	// s = 1 gives y = 0
+	check(&[32]byte{1}, "zero y")
+
+	// Most random strings are not a square or give a negative x*y
+	for i := 0; i < 1000; i++ {
+		rnd.Read(buf[:])
+		buf[0] &^= 1
+		buf[31] &= 0x7f
+		check(&buf, "random")
+	}
+}
+
+func BenchmarkIsValidRistrettoEncoding(b *testing.B) {
+	buf := randomRistrettoEncodings(1)[0]
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		edwards25519.IsValidRistrettoEncoding(&buf)
+	}
+}
+
+func TestRistrettoNegateEncoding(t *testing.T) {
+	var p edwards25519.ExtendedPoint
+	check := func(buf *[32]byte, class string) {
+		var want [32]byte
+		wantOk := p.SetRistretto(buf)
+		if wantOk {
+			p.Neg(&p).RistrettoInto(&want)
+		}
+		got, ok := edwards25519.RistrettoNegateEncoding(buf)
+		if ok != wantOk || got != want {
+			t.Fatalf("RistrettoNegateEncoding(%x) = %x, %v != %x, %v (%s)",
+				buf, got, ok, want, wantOk, class)
+		}
+		if !ok {
+			return
+		}
+		if got, _ = edwards25519.RistrettoNegateEncoding(&got); got != *buf {
+			t.Fatalf("RistrettoNegateEncoding twice: %x != %x (%s)",
+				got, buf, class)
+		}
+	}
+
+	bufs := randomRistrettoEncodings(100)
+	for i := range bufs {
+		check(&bufs[i], "valid")
+		bufs[i][0] |= 1
+		check(&bufs[i], "negative s")
+		bufs[i][0] &^= 1
+		bufs[i][31] |= 0x80
+		check(&bufs[i], "high bit set")
+	}
+
+	var buf [32]byte
+	check(&buf, "zero")
+
+	// s = p is a non-canonical encoding of zero
+	for i := 0; i < 32; i++ {
+		buf[i] = 0xff
+	}
+	buf[0] = 0xed
+	buf[31] = 0x7f
+	check(&buf, "non-canonical")
+
+	// s = 1 gives y = 0
+	check(&[32]byte{1}, "zero y")
+
+	for i := 0; i < 1000; i++ {
+		rnd.Read(buf[:])
+		buf[0] &^= 1
+		buf[31] &= 0x7f
+		check(&buf, "random")
+	}
+}
+
+func BenchmarkRistrettoNegateEncoding(b *testing.B) {
+	buf := randomRistrettoEncodings(1)[0]
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		edwards25519.RistrettoNegateEncoding(&buf)
+	}
+}
+
+func TestScalarMultLowMem(t *testing.T) {
+	var p, q, want edwards25519.ExtendedPoint
+	var fe edwards25519.FieldElement
+	var cp edwards25519.CompletedPoint
+	var s [32]byte
+	for i := 0; i < 100; i++ {
+		rnd.Read(s[:])
+		fe.SetBytes(&s)
+		q.SetCompleted(cp.SetRistrettoElligator2(&fe))
+		rnd.Read(s[:])
+		s[31] &= 15
+		want.ScalarMult(&q, &s)
+		if !p.ScalarMultLowMem(&q, &s).Equals(&want) {
+			t.Fatalf("ScalarMultLowMem(%v, %x) = %v != %v", &q, s, &p, &want)
+		}
+		if !q.ScalarMultLowMem(&q, &s).Equals(&want) {
+			t.Fatalf("ScalarMultLowMem does not allow aliasing")
+		}
+	}
+
+	allocs := testing.AllocsPerRun(10, func() {
+		p.ScalarMultLowMem(&q, &s)
+	})
+	if allocs != 0 {
+		t.Fatalf("ScalarMultLowMem allocates %v times", allocs)
+	}
+}
+
+func BenchmarkScalarMultLowMem(b *testing.B) {
+	var fe edwards25519.FieldElement
+	var cp edwards25519.CompletedPoint
+	var ep edwards25519.ExtendedPoint
+	var buf [32]byte
+	rnd.Read(buf[:])
+	fe.SetBytes(&buf)
+	cp.SetRistrettoElligator2(&fe)
+	ep.SetCompleted(&cp)
+	rnd.Read(buf[:])
+	buf[31] &= 15
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		ep.ScalarMultLowMem(&ep, &buf)
+	}
+}
+
 func BenchmarkScalarMult(b *testing.B) {
 	var buf, sBuf [32]byte
 	var biS big.Int
@@ -221,3 +826,503 @@ func BenchmarkScalarMult(b *testing.B) {
 		ep.ScalarMult(&ep, &sBuf)
 	}
 }
+
+func TestScalarMultInto(t *testing.T) {
+	var buf, sBuf [32]byte
+	var fe edwards25519.FieldElement
+	var cp edwards25519.CompletedPoint
+	var ep, want, got edwards25519.ExtendedPoint
+	var scratch edwards25519.ScalarMultScratch
+	for i := 0; i < 100; i++ {
+		rnd.Read(buf[:])
+		rnd.Read(sBuf[:])
+		sBuf[31] &= 15
+		fe.SetBytes(&buf)
+		ep.SetCompleted(cp.SetRistrettoElligator2(&fe))
+		want.ScalarMult(&ep, &sBuf)
+		edwards25519.ScalarMultInto(&got, &ep, &sBuf, &scratch)
+		if got.RistrettoEqualsI(&want) != 1 {
+			t.Fatalf("ScalarMultInto() = %v != %v", got, want)
+		}
+	}
+}
+
+func BenchmarkScalarMultInto(b *testing.B) {
+	var buf, sBuf [32]byte
+	var fe edwards25519.FieldElement
+	var cp edwards25519.CompletedPoint
+	var ep edwards25519.ExtendedPoint
+	var scratch edwards25519.ScalarMultScratch
+	rnd.Read(buf[:])
+	rnd.Read(sBuf[:])
+	sBuf[31] &= 15
+	fe.SetBytes(&buf)
+	ep.SetCompleted(cp.SetRistrettoElligator2(&fe))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		edwards25519.ScalarMultInto(&ep, &ep, &sBuf, &scratch)
+	}
+}
+
+func TestAssertCanonical(t *testing.T) {
+	var buf [32]byte
+	var fe edwards25519.FieldElement
+	var cp edwards25519.CompletedPoint
+	var ep edwards25519.ExtendedPoint
+	for i := 0; i < 100; i++ {
+		rnd.Read(buf[:])
+		fe.SetBytes(&buf)
+		ep.SetCompleted(cp.SetRistrettoElligator2(&fe))
+		ep.RistrettoInto(&buf)
+		if err := edwards25519.AssertCanonical(&buf); err != nil {
+			t.Fatalf("AssertCanonical: %v", err)
+		}
+
+		// s is negative
+		buf[0] |= 1
+		if edwards25519.AssertCanonical(&buf) == nil {
+			t.Fatalf("AssertCanonical accepts negative s")
+		}
+	}
+
+	// s = p is not reduced
+	buf = [32]byte{0xed}
+	for i := 1; i < 31; i++ {
+		buf[i] = 0xff
+	}
+	buf[31] = 0x7f
+	if edwards25519.AssertCanonical(&buf) == nil {
+		t.Fatalf("AssertCanonical accepts s = p")
+	}
+
+	// The high bit set
+	buf = [32]byte{}
+	buf[31] = 0x80
+	if edwards25519.AssertCanonical(&buf) == nil {
+		t.Fatalf("AssertCanonical accepts s >= 2^255")
+	}
+}
+
+func TestRistrettoAppend(t *testing.T) {
+	var buf, buf2 [32]byte
+	var fe edwards25519.FieldElement
+	var cp edwards25519.CompletedPoint
+	var ep, ep2 edwards25519.ExtendedPoint
+	for i := 0; i < 100; i++ {
+		rnd.Read(buf[:])
+		fe.SetBytes(&buf)
+		ep.SetCompleted(cp.SetRistrettoElligator2(&fe))
+		ep2.Set(&ep)
+
+		prefix := []byte("prefix")
+		out := ep.RistrettoAppend(prefix)
+		ep.RistrettoInto(&buf)
+		if !bytes.Equal(out[:6], prefix) || !bytes.Equal(out[6:], buf[:]) {
+			t.Fatalf("RistrettoAppend() = %x", out)
+		}
+
+		// Decode into the same point object
+		if !ep.SetRistretto(&buf) {
+			t.Fatalf("SetRistretto fails on RistrettoInto() output")
+		}
+		if ep.RistrettoEqualsI(&ep2) != 1 {
+			t.Fatalf("Encode-decode in place changed %v into %v", ep2, ep)
+		}
+		ep.RistrettoInto(&buf2)
+		if buf != buf2 {
+			t.Fatalf("Re-encoding gives %x != %x", buf2, buf)
+		}
+	}
+}
+
+// Returns the compressed Edwards encoding of p.
+func edwardsEncoding(p *edwards25519.ExtendedPoint) [32]byte {
+	var x, y, zInv edwards25519.FieldElement
+	var buf [32]byte
+	zInv.Inverse(&p.Z)
+	x.Mul(&p.X, &zInv)
+	y.Mul(&p.Y, &zInv)
+	y.BytesInto(&buf)
+	buf[31] |= byte(x.IsNegativeI() << 7)
+	return buf
+}
+
+func TestSetEdwardsClearTorsion(t *testing.T) {
+	var B, T, P, want edwards25519.ExtendedPoint
+	B.SetBase()
+	want.Double(&B).Double(&want).Double(&want)
+
+	// The standard Ed25519 basepoint and a point of order 8.
+	var stdB, T8 [32]byte
+	hex.Decode(stdB[:], []byte("5866666666666666666666666666666666666666666666666666666666666666"))
+	hex.Decode(T8[:], []byte("c7176a703d4dd84fba3c0b760d10670f2a2053fa2c39ccc64ec7fd7792ac037a"))
+	if !P.SetEdwardsClearTorsion(&stdB) || P.RistrettoEqualsI(&want) != 1 {
+		t.Fatalf("SetEdwardsClearTorsion(B) != 8B")
+	}
+	if !P.SetEdwardsClearTorsion(&T8) || P.RistrettoEqualsI(new(edwards25519.ExtendedPoint).SetZero()) != 1 {
+		t.Fatalf("SetEdwardsClearTorsion(T8) != 0")
+	}
+
+	// B plus each point of order dividing 4
+	for _, torsion := range []*edwards25519.ExtendedPoint{
+		T.SetZero(), new(edwards25519.ExtendedPoint).SetTorsion1(),
+		new(edwards25519.ExtendedPoint).SetTorsion2(),
+		new(edwards25519.ExtendedPoint).SetTorsion3(),
+	} {
+		P.Add(&B, torsion)
+		buf := edwardsEncoding(&P)
+		if !P.SetEdwardsClearTorsion(&buf) {
+			t.Fatalf("SetEdwardsClearTorsion(%x) fails", buf)
+		}
+		if P.RistrettoEqualsI(&want) != 1 {
+			t.Fatalf("SetEdwardsClearTorsion(%x) = %v != %v", buf, P, want)
+		}
+	}
+
+	// y = 2 is not on the curve; y = p is not canonical.
+	buf := [32]byte{2}
+	if P.SetEdwardsClearTorsion(&buf) {
+		t.Fatalf("SetEdwardsClearTorsion accepts y = 2")
+	}
+	buf = [32]byte{0xed}
+	for i := 1; i < 31; i++ {
+		buf[i] = 0xff
+	}
+	buf[31] = 0x7f
+	if P.SetEdwardsClearTorsion(&buf) {
+		t.Fatalf("SetEdwardsClearTorsion accepts y = p")
+	}
+}
+
+func TestDoubleN(t *testing.T) {
+	var buf, eight [32]byte
+	var fe edwards25519.FieldElement
+	var cp edwards25519.CompletedPoint
+	var q, want, got edwards25519.ExtendedPoint
+	eight[0] = 8
+	for i := 0; i < 100; i++ {
+		rnd.Read(buf[:])
+		fe.SetBytes(&buf)
+		q.SetCompleted(cp.SetRistrettoElligator2(&fe))
+		want.Set(&q)
+		for n := 0; n < 7; n++ {
+			got.DoubleN(&q, n)
+			if got.RistrettoEqualsI(&want) != 1 {
+				t.Fatalf("DoubleN(%v, %d) = %v != %v", q, n, got, want)
+			}
+			want.Double(&want)
+		}
+
+		// Give q a torsion component.  Ristretto equality ignores it,
+		// so compare the Edwards points themselves.
+		q.Add(&q, new(edwards25519.ExtendedPoint).SetTorsion2())
+		got.DoubleN(&q, 3)
+		want.MulByCofactor(&q)
+		if edwardsEncoding(&got) != edwardsEncoding(&want) {
+			t.Fatalf("DoubleN(q, 3) = %v != MulByCofactor(q) = %v", got, want)
+		}
+		want.ScalarMult(&q, &eight)
+		if edwardsEncoding(&got) != edwardsEncoding(&want) {
+			t.Fatalf("DoubleN(q, 3) = %v != 8q = %v", got, want)
+		}
+	}
+}
+
+func TestEncodePair(t *testing.T) {
+	var buf [32]byte
+	var fe edwards25519.FieldElement
+	var cp edwards25519.CompletedPoint
+	var p, q edwards25519.ExtendedPoint
+	for i := 0; i < 100; i++ {
+		rnd.Read(buf[:])
+		fe.SetBytes(&buf)
+		p.SetCompleted(cp.SetRistrettoElligator2(&fe))
+		rnd.Read(buf[:])
+		fe.SetBytes(&buf)
+		q.SetCompleted(cp.SetRistrettoElligator2(&fe))
+
+		enc := edwards25519.EncodePair(&p, &q)
+		p.RistrettoInto(&buf)
+		if !bytes.Equal(enc[:32], buf[:]) {
+			t.Fatalf("EncodePair() first half %x != %x", enc[:32], buf)
+		}
+		a, b, ok := edwards25519.DecodePair(&enc)
+		if !ok {
+			t.Fatalf("DecodePair(%x) fails", enc)
+		}
+		if a.RistrettoEqualsI(&p) != 1 || b.RistrettoEqualsI(&q) != 1 {
+			t.Fatalf("DecodePair(EncodePair(%v, %v)) = %v, %v", p, q, a, b)
+		}
+
+		// Invalidate either half
+		enc[0] |= 1
+		if _, _, ok := edwards25519.DecodePair(&enc); ok {
+			t.Fatalf("DecodePair accepts an invalid first half")
+		}
+		enc[0] &^= 1
+		enc[32] |= 1
+		if _, _, ok := edwards25519.DecodePair(&enc); ok {
+			t.Fatalf("DecodePair accepts an invalid second half")
+		}
+	}
+}
+
+func TestEncodeDelta(t *testing.T) {
+	var buf [32]byte
+	var fe edwards25519.FieldElement
+	var cp edwards25519.CompletedPoint
+	var prev, cur, step edwards25519.ExtendedPoint
+	for i := 0; i < 100; i++ {
+		rnd.Read(buf[:])
+		fe.SetBytes(&buf)
+		prev.SetCompleted(cp.SetRistrettoElligator2(&fe))
+		rnd.Read(buf[:])
+		fe.SetBytes(&buf)
+		cur.SetCompleted(cp.SetRistrettoElligator2(&fe))
+
+		delta := edwards25519.EncodeDelta(&prev, &cur)
+		got, ok := edwards25519.ApplyDelta(&prev, &delta)
+		if !ok {
+			t.Fatalf("ApplyDelta(%v, %x) fails", &prev, delta)
+		}
+		if !got.Equals(&cur) {
+			t.Fatalf("ApplyDelta(EncodeDelta(%v, %v)) = %v", &prev, &cur, got)
+		}
+
+		delta[0] |= 1
+		if _, ok := edwards25519.ApplyDelta(&prev, &delta); ok {
+			t.Fatalf("ApplyDelta accepts an invalid delta")
+		}
+	}
+
+	// Points advancing by a fixed step have the same delta
+	step.SetBase()
+	cur.Add(&prev, &step)
+	delta := edwards25519.EncodeDelta(&prev, &cur)
+	step.RistrettoInto(&buf)
+	if delta != buf {
+		t.Fatalf("EncodeDelta(p, p + B) = %x != %x", delta, buf)
+	}
+}
+
+func TestTorsionPoints(t *testing.T) {
+	var p, zero edwards25519.ExtendedPoint
+	zero.SetZero()
+	torsion := edwards25519.TorsionPoints()
+	seen := make(map[[32]byte]bool)
+	for i := 0; i < 8; i++ {
+		p.MulByCofactor(&torsion[i])
+		if edwardsEncoding(&p) != edwardsEncoding(&zero) {
+			t.Fatalf("8 * TorsionPoints()[%d] = %v != 0", i, p)
+		}
+		if torsion[i].IsSmallOrderI() != 1 {
+			t.Fatalf("TorsionPoints()[%d] does not have small order", i)
+		}
+		if i%2 == 0 && torsion[i].RistrettoEqualsI(&zero) != 1 {
+			t.Fatalf("TorsionPoints()[%d] is not Ristretto-equivalent to 0", i)
+		}
+		seen[edwardsEncoding(&torsion[i])] = true
+	}
+	if len(seen) != 8 {
+		t.Fatalf("TorsionPoints() are not distinct")
+	}
+
+	// The points are cached, but callers get a copy
+	want := edwardsEncoding(&torsion[1])
+	torsion[1].SetZero()
+	torsion = edwards25519.TorsionPoints()
+	if edwardsEncoding(&torsion[1]) != want {
+		t.Fatalf("Modifying the result changes later TorsionPoints()")
+	}
+
+	p.SetBase()
+	if p.IsSmallOrderI() != 0 {
+		t.Fatalf("Basepoint has small order")
+	}
+	p.Add(&p, &torsion[3])
+	if p.IsSmallOrderI() != 0 {
+		t.Fatalf("Basepoint plus torsion has small order")
+	}
+}
+
+func TestEqualsProjectiveExtendedI(t *testing.T) {
+	var buf [32]byte
+	var fe edwards25519.FieldElement
+	var cp edwards25519.CompletedPoint
+	var pp edwards25519.ProjectivePoint
+	var q, q2, conv edwards25519.ExtendedPoint
+	var torsion edwards25519.ExtendedPoint
+	torsion.SetTorsion2()
+	for i := 0; i < 100; i++ {
+		rnd.Read(buf[:])
+		fe.SetBytes(&buf)
+		q.SetCompleted(cp.SetRistrettoElligator2(&fe))
+
+		// 2q in projective coordinates against 2q and 2q + T in extended
+		// coordinates, compared with and without converting pp.
+		cp.DoubleExtended(&q)
+		pp.SetCompleted(&cp)
+		conv.X.Mul(&pp.X, &pp.Z)
+		conv.Y.Mul(&pp.Y, &pp.Z)
+		conv.Z.Square(&pp.Z)
+		conv.T.Mul(&pp.X, &pp.Y)
+		for j := 0; j < 2; j++ {
+			q2.Double(&q)
+			if j == 1 {
+				q2.Add(&q2, &torsion)
+			}
+			want := int32(0)
+			if edwardsEncoding(&conv) == edwardsEncoding(&q2) {
+				want = 1
+			}
+			if want != int32(1-j) {
+				t.Fatalf("Reference comparison is broken")
+			}
+			if edwards25519.EqualsProjectiveExtendedI(&pp, &q2) != want {
+				t.Fatalf("EqualsProjectiveExtendedI(%v, %v) != %d", pp, q2, want)
+			}
+		}
+	}
+}
+
+func TestSetBaseIf(t *testing.T) {
+	var p, base, zero edwards25519.ExtendedPoint
+	base.SetBase()
+	zero.SetZero()
+	if p.SetBaseIf(1).RistrettoEqualsI(&base) != 1 {
+		t.Fatalf("SetBaseIf(1) != base")
+	}
+	if p.SetBaseIf(0).RistrettoEqualsI(&zero) != 1 {
+		t.Fatalf("SetBaseIf(0) != zero")
+	}
+}
+
+// Returns the clamped X25519 private key k divided by the cofactor 8
+// modulo the group order.
+func clampAndReduce(k []byte) [32]byte {
+	var buf [32]byte
+	copy(buf[:], k)
+	buf[0] &= 248
+	buf[31] &= 127
+	buf[31] |= 64
+	for i := 0; i < 16; i++ {
+		buf[i], buf[31-i] = buf[31-i], buf[i]
+	}
+	var l, x big.Int
+	l.SetString("7237005577332262213973186563042994240857116359379907606001950938285454250989", 10)
+	x.SetBytes(buf[:])
+	x.Rsh(&x, 3)
+	x.Mod(&x, &l)
+	xBytes := x.Bytes()
+	for i := 0; i < 32; i++ {
+		buf[i] = 0
+	}
+	for i := 0; i < len(xBytes); i++ {
+		buf[i] = xBytes[len(xBytes)-1-i]
+	}
+	return buf
+}
+
+func TestMontgomeryUInto(t *testing.T) {
+	var B, A, S edwards25519.ExtendedPoint
+	var buf [32]byte
+
+	// Test vectors from RFC 7748, section 6.1.  A clamped private key k
+	// is a multiple of 8, so X25519 computes (k/8) * (8*P), where 8*P
+	// has prime order.
+	B.SetBase()
+	B.Double(&B).Double(&B).Double(&B)
+	aPriv, _ := hex.DecodeString("77076d0a7318a57d3c16c17251b26645df4c2f87ebc0992ab177fba51db92c2a")
+	bPriv, _ := hex.DecodeString("5dab087e624a8a4b79e17f8b83800ee66f3bb1292618b6fd1c2f8b27ff88e0eb")
+	a := clampAndReduce(aPriv)
+	b := clampAndReduce(bPriv)
+	A.ScalarMult(&B, &a)
+	A.MontgomeryUInto(&buf)
+	if hex.EncodeToString(buf[:]) != "8520f0098930a754748b7ddcb43ef75a0dbf3a0d26381af4eba4a98eaa9b4e6a" {
+		t.Fatalf("MontgomeryUInto(a*B) = %x", buf)
+	}
+	A.Double(&A).Double(&A).Double(&A)
+	S.ScalarMult(&A, &b)
+	S.MontgomeryUInto(&buf)
+	if hex.EncodeToString(buf[:]) != "4a5d9d5ba4ce2de1728e3bf480350f25e07e21c947d19e3376f09b3c1e161742" {
+		t.Fatalf("MontgomeryUInto(b*a*B) = %x", buf)
+	}
+}
+
+// Best-effort check that the running time of ScalarMult() does not depend
+// on the Hamming weight of the scalar.  Timing is noisy, so this compares
+// medians with a generous margin.  As it still fails on loaded machines,
+// it only runs if the environment variable RISTRETTO_TIMING_TEST is set.
+func TestScalarMultTiming(t *testing.T) {
+	if os.Getenv("RISTRETTO_TIMING_TEST") == "" {
+		t.Skip("set RISTRETTO_TIMING_TEST to run the timing test")
+	}
+	var low, high [32]byte
+	var p, q edwards25519.ExtendedPoint
+	low[0] = 1
+	for i := 0; i < 31; i++ {
+		high[i] = 0xff
+	}
+	high[31] = 0x0f
+	q.SetBase()
+
+	const samples = 301
+	var lowTimes, highTimes [samples]time.Duration
+	for i := 0; i < samples; i++ {
+		start := time.Now()
+		p.ScalarMult(&q, &low)
+		lowTimes[i] = time.Since(start)
+		start = time.Now()
+		p.ScalarMult(&q, &high)
+		highTimes[i] = time.Since(start)
+	}
+	median := func(ts []time.Duration) time.Duration {
+		sort.Slice(ts, func(i, j int) bool { return ts[i] < ts[j] })
+		return ts[len(ts)/2]
+	}
+	lowMedian := median(lowTimes[:])
+	highMedian := median(highTimes[:])
+	ratio := float64(highMedian) / float64(lowMedian)
+	if ratio > 1.3 || ratio < 1/1.3 {
+		t.Fatalf("ScalarMult timing depends on the scalar: median %v for "+
+			"weight 1 and %v for weight 252", lowMedian, highMedian)
+	}
+}
+
+func TestSafeSum(t *testing.T) {
+	var buf [32]byte
+	var fe edwards25519.FieldElement
+	var cp edwards25519.CompletedPoint
+	var q, want, zero, lq edwards25519.ExtendedPoint
+	var l [32]byte
+	hex.Decode(l[:], []byte(
+		"edd3f55c1a631258d69cf7a2def9de1400000000000000000000000000000010"))
+	torsion := edwards25519.TorsionPoints()
+	zero.SetZero()
+
+	clean := make([]*edwards25519.ExtendedPoint, 5)
+	tweaked := make([]*edwards25519.ExtendedPoint, 5)
+	want.SetZero()
+	for i := range clean {
+		rnd.Read(buf[:])
+		fe.SetBytes(&buf)
+		q.SetCompleted(cp.SetRistrettoElligator2(&fe))
+		clean[i] = edwards25519.SafeSum([]*edwards25519.ExtendedPoint{&q})
+		if edwardsEncoding(lq.ScalarMult(clean[i], &l)) != edwardsEncoding(&zero) {
+			t.Fatalf("SafeSum(%v) does not have prime order", &q)
+		}
+		tweaked[i] = new(edwards25519.ExtendedPoint).Add(clean[i], &torsion[i+1])
+		want.Add(&want, clean[i])
+	}
+
+	if edwardsEncoding(edwards25519.SafeSum(clean)) != edwardsEncoding(&want) {
+		t.Fatalf("SafeSum changes a sum of prime-order points")
+	}
+	if edwardsEncoding(edwards25519.SafeSum(tweaked)) != edwardsEncoding(&want) {
+		t.Fatalf("SafeSum does not remove torsion")
+	}
+	if edwardsEncoding(edwards25519.SafeSum(nil)) != edwardsEncoding(&zero) {
+		t.Fatalf("SafeSum(nil) != 0")
+	}
+}
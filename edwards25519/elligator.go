@@ -149,7 +149,7 @@ func (p *JacobiPoint) Dual(q *JacobiPoint) *JacobiPoint {
 //
 // Returns 1 if a preimage is found and 0 if none exists.
 func (p *JacobiPoint) elligator2Inverse(fe *FieldElement) int {
-	var x, y, a, a2, S2, S4, invSqiY, negS2, out FieldElement
+	var x, y, a, a2, S2, S4, invSqiY, out FieldElement
 
 	// Special case: s = 0.  If s is zero, either t = 1 or t = -1.
 	// If t=1, then sqrt(i*d) is the preimage.  Otherwise it's 0.
@@ -178,8 +178,7 @@ func (p *JacobiPoint) elligator2Inverse(fe *FieldElement) int {
 	done |= sq
 
 	// x := (a + sign(s)*s^2) y
-	negS2.Neg(&S2)
-	S2.ConditionalSet(&negS2, p.S.IsNegativeI())
+	S2.ConditionalNegate(p.S.IsNegativeI())
 	x.add(&a, &S2)
 	x.Mul(&x, &y)
 
@@ -215,7 +214,7 @@ func (p *CompletedPoint) SetJacobiQuartic(jc *JacobiPoint) *CompletedPoint {
 // on Elligator2 for Ristretto.  Returns p.
 func (p *CompletedPoint) SetRistrettoElligator2(r0 *FieldElement) *CompletedPoint {
 	var r, rPlusD, rPlusOne, D, N, ND, sqrt, twiddle, sgn FieldElement
-	var rSubOne, r0i, sNeg FieldElement
+	var rSubOne, r0i FieldElement
 	var jc JacobiPoint
 
 	var b int32
@@ -260,8 +259,7 @@ func (p *CompletedPoint) SetRistrettoElligator2(r0 *FieldElement) *CompletedPoin
 	jc.T.Mul(&rSubOne, &jc.T)
 	jc.T.sub(&jc.T, &feOne)
 
-	sNeg.Neg(&jc.S)
-	jc.S.ConditionalSet(&sNeg, equal30(jc.S.IsNegativeI(), b))
+	jc.S.ConditionalNegate(equal30(jc.S.IsNegativeI(), b))
 	return p.SetJacobiQuartic(&jc)
 }
 
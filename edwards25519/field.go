@@ -6,6 +6,7 @@ import (
 	"math/big"
 
 	"encoding/binary"
+	"fmt"
 )
 
 // Set fe to i, the root of -1.  Returns fe.
@@ -14,6 +15,23 @@ func (fe *FieldElement) SetI() *FieldElement {
 	return fe
 }
 
+// Returns a copy of the curve constant d = -121665/121666 of Edwards25519.
+func D() FieldElement {
+	return feD
+}
+
+// Returns a copy of the constant i, the square root of -1 used
+// internally.  See also SetI().
+func SqrtMinusOne() FieldElement {
+	return feI
+}
+
+// Returns a copy of the constant 1/sqrt(-d-1) used in the Ristretto
+// encoding.
+func InvSqrtMinusDMinusOne() FieldElement {
+	return feInvSqrtMinusDMinusOne
+}
+
 // Set fe to 0.  Returns fe.
 func (fe *FieldElement) SetZero() *FieldElement {
 	copy(fe[:], feZero[:])
@@ -56,6 +74,7 @@ func (fe *FieldElement) Bytes() [32]byte {
 
 // Set fe to the inverse of a.  Return fe.
 func (fe *FieldElement) Inverse(a *FieldElement) *FieldElement {
+	countInverse()
 	var t0, t1, t2, t3 FieldElement
 	var i int
 
@@ -108,6 +127,66 @@ func (fe *FieldElement) Inverse(a *FieldElement) *FieldElement {
 	return fe.Mul(&t1, &t0)
 }
 
+// Sets out[i] to the inverse of in[i] for every i, or to zero if in[i]
+// is zero.  out and in may be the same slice.  Panics if they have
+// different lengths.
+//
+// This uses Montgomery's trick: it computes the running products of the
+// elements, inverts the last one and then works backwards, so that n
+// inversions cost a single Inverse() and about 3n multiplications.  Zeros
+// are replaced by one in the products, so that they do not spoil the
+// other inverses; like Inverse(), this is constant-time.
+func BatchInverse(out, in []FieldElement) {
+	var acc, inv, x FieldElement
+	if len(out) != len(in) {
+		panic("edwards25519: BatchInverse: lengths of out and in differ")
+	}
+	if len(in) == 0 {
+		return
+	}
+	products := make([]FieldElement, len(in))
+	nonZero := make([]int32, len(in))
+
+	acc.SetOne()
+	for i := 0; i < len(in); i++ {
+		x.Set(&in[i])
+		nonZero[i] = x.IsNonZeroI()
+		x.ConditionalSet(&feOne, 1-nonZero[i])
+		acc.Mul(&acc, &x)
+		products[i].Set(&acc)
+	}
+
+	inv.Inverse(&acc)
+	for i := len(in) - 1; i > 0; i-- {
+		x.Set(&in[i])
+		x.ConditionalSet(&feOne, 1-nonZero[i])
+		out[i].Mul(&inv, &products[i-1])
+		out[i].ConditionalSet(&feZero, 1-nonZero[i])
+		inv.Mul(&inv, &x)
+	}
+	out[0].Set(&inv)
+	out[0].ConditionalSet(&feZero, 1-nonZero[0])
+}
+
+// Set fe to table[index] by scanning all of table, so that the running
+// time does not depend on index.  Sets fe to zero if index is out of range.
+// Assumes 0 <= index < 2^30.  Returns fe.
+func (fe *FieldElement) SelectFrom(table []FieldElement, index int32) *FieldElement {
+	fe.SetZero()
+	for i := 0; i < len(table); i++ {
+		fe.ConditionalSet(&table[i], equal30(int32(i), index))
+	}
+	return fe
+}
+
+// Set fe to -fe if b == 1.  Requires b to be either 0 or 1.  Returns fe.
+func (fe *FieldElement) ConditionalNegate(b int32) *FieldElement {
+	var feNeg FieldElement
+	feNeg.Neg(fe)
+	fe.ConditionalSet(&feNeg, b)
+	return fe
+}
+
 // Set fe to -x if x is negative and x otherwise.  Returns fe.
 func (fe *FieldElement) Abs(x *FieldElement) *FieldElement {
 	var xNeg FieldElement
@@ -148,6 +227,9 @@ func (fe *FieldElement) IsOneI() int32 {
 }
 
 // Returns 1 if fe is equal to a, otherwise 0.
+//
+// The difference is fully reduced before it is compared to zero, so the
+// result does not depend on how fe and a are represented.
 func (fe *FieldElement) EqualsI(a *FieldElement) int32 {
 	var b FieldElement
 	return 1 - b.sub(fe, a).IsNonZeroI()
@@ -197,6 +279,73 @@ func (fe *FieldElement) SetBigInt(x *big.Int) *FieldElement {
 	return fe.SetBytes(&rBuf)
 }
 
+// Returns the bit-width of each value when packing n values with
+// PackUint64s().
+func packWidth(n int) uint {
+	if n < 4 {
+		return 64
+	}
+	return uint(252 / n)
+}
+
+// Sets fe to the field element whose i-th group of w bits is values[i],
+// where w = min(64, 252/len(values)).  Returns fe.  Returns an error if
+// there are no or more than 252 values, or if a value does not fit in w bits.
+// Unpack with UnpackUint64s().
+func (fe *FieldElement) PackUint64s(values []uint64) (*FieldElement, error) {
+	var buf [32]byte
+	if len(values) == 0 || len(values) > 252 {
+		return nil, fmt.Errorf("Can't pack %d values", len(values))
+	}
+	w := packWidth(len(values))
+	for i, v := range values {
+		if w < 64 && v>>w != 0 {
+			return nil, fmt.Errorf("Value %d does not fit in %d bits", v, w)
+		}
+		for j := uint(0); j < w; j++ {
+			pos := uint(i)*w + j
+			buf[pos/8] |= byte((v>>j)&1) << (pos % 8)
+		}
+	}
+	return fe.SetBytes(&buf), nil
+}
+
+// Returns the n values packed into fe by PackUint64s().
+func (fe *FieldElement) UnpackUint64s(n int) []uint64 {
+	var buf [32]byte
+	if n <= 0 || n > 252 {
+		return nil
+	}
+	fe.BytesInto(&buf)
+	w := packWidth(n)
+	ret := make([]uint64, n)
+	for i := 0; i < n; i++ {
+		for j := uint(0); j < w; j++ {
+			pos := uint(i)*w + j
+			ret[i] |= uint64((buf[pos/8]>>(pos%8))&1) << j
+		}
+	}
+	return ret
+}
+
+// Sets fe to x^(2^iterations) by squaring x iterations times in a row.
+// Returns fe.
+//
+// This is the primitive of time-lock puzzles and verifiable delay
+// functions: each squaring needs the result of the previous one, so the
+// running time is linear in iterations and can not be parallelized.  As
+// the order of the multiplicative group is known, anyone can shortcut it
+// by reducing 2^iterations modulo p - 1 first; this function deliberately
+// does not.  Large iteration counts take correspondingly long.
+func (fe *FieldElement) SequentialSquare(x *FieldElement,
+	iterations uint64) *FieldElement {
+	fe.Set(x)
+	for i := uint64(0); i < iterations; i++ {
+		fe.Square(fe)
+	}
+	return fe
+}
+
 // Sets fe to a^( ((2^255 - 19) - 5) / 8 ) = a^(2^252 - 3).  Returns fe.
 //
 // This method is useful to compute (inverse) square-roots
@@ -331,6 +480,53 @@ func (fe *FieldElement) InvSqrtI(a *FieldElement) int32 {
 	return inCaseA + inCaseB
 }
 
+// Sets fe to the non-negative square root of u/v if it exists and to the
+// non-negative square root of i*u/v otherwise.  Returns 1 in the former
+// case and 0 in the latter.  If u is zero, sets fe to zero and returns 1;
+// if u is non-zero and v is zero, sets fe to zero and returns 0.
+//
+// This is the SQRT_RATIO_M1 function from the ristretto255 specification.
+func (fe *FieldElement) SqrtRatioI(u, v *FieldElement) int32 {
+	var v3, v7, r, rPrime, check, uNeg, uNegI FieldElement
+
+	// r = (u v^3) (u v^7)^((p-5)/8)
+	v3.Square(v)
+	v3.Mul(&v3, v)
+	v7.Square(&v3)
+	v7.Mul(&v7, v)
+	r.Mul(u, &v7)
+	r.Exp22523(&r)
+	r.Mul(&r, &v3)
+	r.Mul(&r, u)
+
+	check.Square(&r)
+	check.Mul(&check, v)
+	uNeg.Neg(u)
+	uNegI.Mul(&uNeg, &feI)
+	correctSign := check.EqualsI(u)
+	flippedSign := check.EqualsI(&uNeg)
+	flippedSignI := check.EqualsI(&uNegI)
+
+	rPrime.Mul(&r, &feI)
+	r.ConditionalSet(&rPrime, flippedSign|flippedSignI)
+	fe.Abs(&r)
+	return correctSign | flippedSign
+}
+
+// Returns 1 if fe is a square (including zero) and 0 otherwise.
+func (fe *FieldElement) IsSquareI() int32 {
+	var t, a2 FieldElement
+
+	// Euler's criterion: fe^((p-1)/2) = fe^(4(2^252-3)+2) is 1 for
+	// non-zero squares.
+	t.Exp22523(fe)
+	t.Square(&t)
+	t.Square(&t)
+	a2.Square(fe)
+	t.Mul(&t, &a2)
+	return t.IsOneI() | (1 - fe.IsNonZeroI())
+}
+
 // Returns 1 if b == c and 0 otherwise.  Assumes 0 <= b, c < 2^30.
 func equal30(b, c int32) int32 {
 	x := uint32(b ^ c)
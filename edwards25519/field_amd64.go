@@ -10,18 +10,21 @@ func feSquare(out, a *FieldElement)
 
 // Sets fe to a * b.  Returns fe.
 func (fe *FieldElement) Mul(a, b *FieldElement) *FieldElement {
+	countMul()
 	feMul(fe, a, b)
 	return fe
 }
 
 // Sets fe to a^2.  Returns fe.
 func (fe *FieldElement) Square(a *FieldElement) *FieldElement {
+	countSquare()
 	feSquare(fe, a)
 	return fe
 }
 
 // Sets fe to 2 * a^2.  Returns fe.
 func (fe *FieldElement) DoubledSquare(a *FieldElement) *FieldElement {
+	countSquare()
 	feSquare(fe, a)
 	return fe.add(fe, fe)
 }
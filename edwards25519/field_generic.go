@@ -348,6 +348,7 @@ func (fe *FieldElement) SetBytes(buf *[32]byte) *FieldElement {
 
 // Sets fe to a * b.  Returns fe.
 func (fe *FieldElement) Mul(a, b *FieldElement) *FieldElement {
+	countMul()
 	a0 := int64(a[0])
 	a1 := int64(a[1])
 	a2 := int64(a[2])
@@ -359,11 +360,11 @@ func (fe *FieldElement) Mul(a, b *FieldElement) *FieldElement {
 	a8 := int64(a[8])
 	a9 := int64(a[9])
 
-	a1_2 := int64(2 * a[1])
-	a3_2 := int64(2 * a[3])
-	a5_2 := int64(2 * a[5])
-	a7_2 := int64(2 * a[7])
-	a9_2 := int64(2 * a[9])
+	a1_2 := 2 * a1
+	a3_2 := 2 * a3
+	a5_2 := 2 * a5
+	a7_2 := 2 * a7
+	a9_2 := 2 * a9
 
 	b0 := int64(b[0])
 	b1 := int64(b[1])
@@ -376,15 +377,15 @@ func (fe *FieldElement) Mul(a, b *FieldElement) *FieldElement {
 	b8 := int64(b[8])
 	b9 := int64(b[9])
 
-	b1_19 := int64(19 * b[1])
-	b2_19 := int64(19 * b[2])
-	b3_19 := int64(19 * b[3])
-	b4_19 := int64(19 * b[4])
-	b5_19 := int64(19 * b[5])
-	b6_19 := int64(19 * b[6])
-	b7_19 := int64(19 * b[7])
-	b8_19 := int64(19 * b[8])
-	b9_19 := int64(19 * b[9])
+	b1_19 := 19 * b1
+	b2_19 := 19 * b2
+	b3_19 := 19 * b3
+	b4_19 := 19 * b4
+	b5_19 := 19 * b5
+	b6_19 := 19 * b6
+	b7_19 := 19 * b7
+	b8_19 := 19 * b8
+	b9_19 := 19 * b9
 
 	h0 := a0*b0 + a1_2*b9_19 + a2*b8_19 + a3_2*b7_19 + a4*b6_19 + a5_2*b5_19 + a6*b4_19 + a7_2*b3_19 + a8*b2_19 + a9_2*b1_19
 	h1 := a0*b1 + a1*b0 + a2*b9_19 + a3*b8_19 + a4*b7_19 + a5*b6_19 + a6*b5_19 + a7*b4_19 + a8*b3_19 + a9*b2_19
@@ -412,14 +413,14 @@ func (fe *FieldElement) square() (h0, h1, h2, h3, h4, h5, h6, h7, h8, h9 int64)
 	f7 := int64(fe[7])
 	f8 := int64(fe[8])
 	f9 := int64(fe[9])
-	f0_2 := int64(2 * fe[0])
-	f1_2 := int64(2 * fe[1])
-	f2_2 := int64(2 * fe[2])
-	f3_2 := int64(2 * fe[3])
-	f4_2 := int64(2 * fe[4])
-	f5_2 := int64(2 * fe[5])
-	f6_2 := int64(2 * fe[6])
-	f7_2 := int64(2 * fe[7])
+	f0_2 := 2 * f0
+	f1_2 := 2 * f1
+	f2_2 := 2 * f2
+	f3_2 := 2 * f3
+	f4_2 := 2 * f4
+	f5_2 := 2 * f5
+	f6_2 := 2 * f6
+	f7_2 := 2 * f7
 	f5_38 := 38 * f5
 	f6_19 := 19 * f6
 	f7_38 := 38 * f7
@@ -442,12 +443,14 @@ func (fe *FieldElement) square() (h0, h1, h2, h3, h4, h5, h6, h7, h8, h9 int64)
 
 // Sets fe to a^2.  Returns fe.
 func (fe *FieldElement) Square(a *FieldElement) *FieldElement {
+	countSquare()
 	h0, h1, h2, h3, h4, h5, h6, h7, h8, h9 := a.square()
 	return fe.setReduced(h0, h1, h2, h3, h4, h5, h6, h7, h8, h9)
 }
 
 // Sets fe to 2 * a^2.  Returns fe.
 func (fe *FieldElement) DoubledSquare(a *FieldElement) *FieldElement {
+	countSquare()
 	h0, h1, h2, h3, h4, h5, h6, h7, h8, h9 := a.square()
 	h0 += h0
 	h1 += h1
@@ -0,0 +1,78 @@
+package edwards25519
+
+import (
+	"math/big"
+	"math/rand"
+	"testing"
+)
+
+// Equal elements that are represented differently must compare equal.
+func TestFeEqualsINonCanonical(t *testing.T) {
+	rnd := rand.New(rand.NewSource(37))
+	var x, chained, four, product FieldElement
+	var buf [32]byte
+	four.SetBytes(&[32]byte{4})
+	for i := 0; i < 1000; i++ {
+		rnd.Read(buf[:])
+		x.SetBytes(&buf)
+
+		// x + x + x + x without reduction in between versus 4 * x
+		chained.add(&x, &x)
+		chained.add(&chained, &x)
+		chained.add(&chained, &x)
+		product.Mul(&x, &four)
+		if chained.EqualsI(&product) != 1 || product.EqualsI(&chained) != 1 {
+			t.Fatalf("EqualsI(%v, %v) != 1", chained, product)
+		}
+		if !chained.Equals(&product) {
+			t.Fatalf("Equals(%v, %v) is false", chained, product)
+		}
+	}
+
+	// 2^255 - 18 = p + 1 is an unreduced encoding of 1
+	for i := 0; i < 32; i++ {
+		buf[i] = 0xff
+	}
+	buf[0] = 0xee
+	buf[31] = 0x7f
+	x.SetBytes(&buf)
+	if x.EqualsI(&feOne) != 1 || feOne.EqualsI(&x) != 1 {
+		t.Fatalf("EqualsI(p+1, 1) != 1")
+	}
+	if x.EqualsI(&feZero) != 0 {
+		t.Fatalf("EqualsI(p+1, 0) != 0")
+	}
+}
+
+// Multiplying elements whose limbs are near the top of their range, as
+// left behind by BytesInto() followed by unreduced additions, must not
+// overflow.
+func TestFeMulLargeLimbs(t *testing.T) {
+	rnd := rand.New(rand.NewSource(37))
+	var x, y, prod, want FieldElement
+	var buf [32]byte
+	for i := 0; i < 1000; i++ {
+		rnd.Read(buf[:])
+		x.SetBytes(&buf)
+		rnd.Read(buf[:])
+		y.SetBytes(&buf)
+		xBi, yBi := x.BigInt(), y.BigInt()
+
+		// BigInt() leaves x and y in the form of BytesInto()
+		x.add(&x, &x)
+		x.add(&x, &x)
+		y.add(&y, &y)
+		y.add(&y, &y)
+		xBi.Lsh(xBi, 2)
+		yBi.Lsh(yBi, 2)
+
+		want.SetBigInt(new(big.Int).Mul(xBi, yBi))
+		if !prod.Mul(&x, &y).Equals(&want) {
+			t.Fatalf("%v * %v = %v != %v", x, y, prod, want)
+		}
+		want.SetBigInt(new(big.Int).Mul(xBi, xBi))
+		if !prod.Square(&x).Equals(&want) {
+			t.Fatalf("%v^2 = %v != %v", x, prod, want)
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 
 // Sets fe to a * b.  Returns fe.
 func (fe *FieldElement) Mul(a, b *FieldElement) *FieldElement {
+	countMul()
 	a0 := a[0]
 	a1 := a[1]
 	a2 := a[2]
@@ -129,6 +130,7 @@ func (fe *FieldElement) Mul(a, b *FieldElement) *FieldElement {
 
 // Sets fe to a^2.  Returns fe.
 func (fe *FieldElement) Square(a *FieldElement) *FieldElement {
+	countSquare()
 	a0 := a[0]
 	a1 := a[1]
 	a2 := a[2]
@@ -211,6 +213,7 @@ func (fe *FieldElement) Square(a *FieldElement) *FieldElement {
 
 // Sets fe to 2 * a^2.  Returns fe.
 func (fe *FieldElement) DoubledSquare(a *FieldElement) *FieldElement {
+	countSquare()
 	a0 := a[0]
 	a1 := a[1]
 	a2 := a[2]
@@ -4,6 +4,7 @@ import (
 	"math/big"
 	"math/rand"
 	"os"
+	"reflect"
 	"testing"
 
 	"github.com/bwesterb/go-ristretto/edwards25519"
@@ -116,6 +117,38 @@ func TestFeInverse(t *testing.T) {
 	}
 }
 
+func TestFeBatchInverse(t *testing.T) {
+	var bi big.Int
+	var want edwards25519.FieldElement
+	for _, n := range []int{0, 1, 2, 5, 64} {
+		in := make([]edwards25519.FieldElement, n)
+		for i := 0; i < n; i++ {
+			if i%3 == 1 {
+				in[i].SetZero()
+				continue
+			}
+			bi.Rand(rnd, &bi25519)
+			in[i].SetBigInt(&bi)
+		}
+		out := make([]edwards25519.FieldElement, n)
+		edwards25519.BatchInverse(out, in)
+		for i := 0; i < n; i++ {
+			want.Inverse(&in[i])
+			if !out[i].Equals(&want) {
+				t.Fatalf("BatchInverse: 1/%v = %v != %v", &in[i], &out[i], &want)
+			}
+		}
+
+		// In place
+		edwards25519.BatchInverse(in, in)
+		for i := 0; i < n; i++ {
+			if !in[i].Equals(&out[i]) {
+				t.Fatalf("BatchInverse in place: %v != %v", &in[i], &out[i])
+			}
+		}
+	}
+}
+
 func TestFeInvSqrtI(t *testing.T) {
 	var bi big.Int
 	var fe1, fe2, feI edwards25519.FieldElement
@@ -154,6 +187,168 @@ func TestFeSqrt(t *testing.T) {
 	}
 }
 
+func TestFeSqrtRatioI(t *testing.T) {
+	var bi big.Int
+	var u, v, r, chk, feI, zero, one, two, four edwards25519.FieldElement
+	feI.SetI()
+	zero.SetZero()
+	one.SetOne()
+	two.Add(&one, &one)
+	four.Add(&two, &two)
+
+	// The test vectors for SQRT_RATIO_M1 of curve25519-dalek.
+	if r.SqrtRatioI(&zero, &zero) != 1 || r.IsNonZeroI() != 0 {
+		t.Fatalf("SqrtRatioI(0, 0) incorrect")
+	}
+	if r.SqrtRatioI(&one, &zero) != 0 || r.IsNonZeroI() != 0 {
+		t.Fatalf("SqrtRatioI(1, 0) incorrect")
+	}
+	chk.Mul(&two, &feI)
+	if r.SqrtRatioI(&two, &one) != 0 || r.IsNegativeI() != 0 ||
+		!chk.Equals(v.Square(&r)) {
+		t.Fatalf("SqrtRatioI(2, 1) incorrect")
+	}
+	if r.SqrtRatioI(&four, &one) != 1 || !r.Equals(&two) {
+		t.Fatalf("SqrtRatioI(4, 1) incorrect")
+	}
+	chk.Inverse(&four)
+	if r.SqrtRatioI(&one, &four) != 1 || r.IsNegativeI() != 0 ||
+		!chk.Equals(v.Square(&r)) {
+		t.Fatalf("SqrtRatioI(1, 4) incorrect")
+	}
+
+	for i := 0; i < 100; i++ {
+		bi.Rand(rnd, &bi25519)
+		u.SetBigInt(&bi)
+		bi.Rand(rnd, &bi25519)
+		v.SetBigInt(&bi)
+		sq := r.SqrtRatioI(&u, &v)
+		if r.IsNegativeI() != 0 {
+			t.Fatalf("SqrtRatioI(%v, %v) is negative", &u, &v)
+		}
+		chk.Square(&r)
+		chk.Mul(&chk, &v)
+		if sq == 0 {
+			u.Mul(&u, &feI)
+		}
+		if !chk.Equals(&u) {
+			t.Fatalf("SqrtRatioI(%v, %v) incorrect", &u, &v)
+		}
+	}
+}
+
+func TestFePackUint64s(t *testing.T) {
+	var fe edwards25519.FieldElement
+	for _, n := range []int{1, 3, 4, 5, 7, 63, 252} {
+		w := uint(64)
+		if 252/n < 64 {
+			w = uint(252 / n)
+		}
+		values := make([]uint64, n)
+		for i := 0; i < n; i++ {
+			values[i] = rnd.Uint64()
+			if w < 64 {
+				values[i] &= (1 << w) - 1
+			}
+		}
+		values[0] = ^uint64(0) >> (64 - w)
+		if _, err := fe.PackUint64s(values); err != nil {
+			t.Fatalf("PackUint64s(%v): %v", values, err)
+		}
+		if !reflect.DeepEqual(fe.UnpackUint64s(n), values) {
+			t.Fatalf("UnpackUint64s(PackUint64s(%v)) = %v", values, fe.UnpackUint64s(n))
+		}
+
+		if w < 64 {
+			values[n-1] = 1 << w
+			if _, err := fe.PackUint64s(values); err == nil {
+				t.Fatalf("PackUint64s accepts a value of %d bits", w+1)
+			}
+		}
+	}
+	if _, err := fe.PackUint64s(make([]uint64, 253)); err == nil {
+		t.Fatalf("PackUint64s accepts 253 values")
+	}
+	if _, err := fe.PackUint64s(nil); err == nil {
+		t.Fatalf("PackUint64s accepts no values")
+	}
+}
+
+func TestFeIsSquareI(t *testing.T) {
+	var bi, d, tmp big.Int
+	var fe, two, feD edwards25519.FieldElement
+	fe.SetZero()
+	if fe.IsSquareI() != 1 {
+		t.Fatalf("IsSquareI(0) != 1")
+	}
+	two.SetOne()
+	two.Add(&two, &two)
+	if two.IsSquareI() != 0 {
+		t.Fatalf("IsSquareI(2) != 0")
+	}
+
+	// d = -121665/121666 is not a square
+	d.SetInt64(-121665)
+	d.Mul(&d, tmp.ModInverse(big.NewInt(121666), &bi25519))
+	feD.SetBigInt(&d)
+	if feD.IsSquareI() != 0 {
+		t.Fatalf("IsSquareI(d) != 0")
+	}
+
+	for i := 0; i < 100; i++ {
+		bi.Rand(rnd, &bi25519)
+		fe.SetBigInt(&bi)
+		fe.Square(&fe)
+		if fe.IsSquareI() != 1 {
+			t.Fatalf("IsSquareI(%v^2) != 1", &bi)
+		}
+		if bi.Sign() == 0 {
+			continue
+		}
+		fe.Mul(&fe, &two)
+		if fe.IsSquareI() != 0 {
+			t.Fatalf("IsSquareI(2*%v^2) != 0", &bi)
+		}
+	}
+}
+
+func TestFeConditionalNegate(t *testing.T) {
+	var bi big.Int
+	var fe1, fe2, neg edwards25519.FieldElement
+	for i := 0; i < 100; i++ {
+		bi.Rand(rnd, &bi25519)
+		fe1.SetBigInt(&bi)
+		neg.Neg(&fe1)
+		fe2.Set(&fe1)
+		if !fe2.ConditionalNegate(0).Equals(&fe1) {
+			t.Fatalf("ConditionalNegate(0) changed %v", &bi)
+		}
+		if !fe2.ConditionalNegate(1).Equals(&neg) {
+			t.Fatalf("ConditionalNegate(1) of %v is not its negation", &bi)
+		}
+	}
+}
+
+func TestFeSelectFrom(t *testing.T) {
+	var bi big.Int
+	var fe edwards25519.FieldElement
+	for _, n := range []int{1, 3, 5, 8} {
+		table := make([]edwards25519.FieldElement, n)
+		for i := 0; i < n; i++ {
+			bi.Rand(rnd, &bi25519)
+			table[i].SetBigInt(&bi)
+		}
+		for i := 0; i < n; i++ {
+			if !fe.SelectFrom(table, int32(i)).Equals(&table[i]) {
+				t.Fatalf("SelectFrom(table, %d) = %v != %v", i, fe, table[i])
+			}
+		}
+		if fe.SelectFrom(table, int32(n)).IsNonZeroI() != 0 {
+			t.Fatalf("SelectFrom(table, %d) is not zero", n)
+		}
+	}
+}
+
 func BenchmarkFeInverse(b *testing.B) {
 	var fe edwards25519.FieldElement
 	var bi big.Int
@@ -165,6 +360,19 @@ func BenchmarkFeInverse(b *testing.B) {
 	}
 }
 
+func BenchmarkFeBatchInverse256(b *testing.B) {
+	var bi big.Int
+	fes := make([]edwards25519.FieldElement, 256)
+	for i := range fes {
+		bi.Rand(rnd, &bi25519)
+		fes[i].SetBigInt(&bi)
+	}
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		edwards25519.BatchInverse(fes, fes)
+	}
+}
+
 func BenchmarkFeSquare(b *testing.B) {
 	var fe edwards25519.FieldElement
 	var bi big.Int
@@ -246,3 +454,67 @@ func TestMain(m *testing.M) {
 	rnd = rand.New(rand.NewSource(37))
 	os.Exit(m.Run())
 }
+
+func TestFieldConstants(t *testing.T) {
+	var fe, want, minusOne edwards25519.FieldElement
+	var p, num, den big.Int
+	minusOne.SetOne()
+	minusOne.Neg(&minusOne)
+
+	i := edwards25519.SqrtMinusOne()
+	if !fe.Square(&i).Equals(&minusOne) {
+		t.Fatalf("SqrtMinusOne()^2 = %v != -1", &fe)
+	}
+	if !i.Equals(want.SetI()) {
+		t.Fatalf("SqrtMinusOne() = %v != SetI() = %v", &i, &want)
+	}
+
+	// d = -121665/121666 mod 2^255-19
+	p.SetString("57896044618658097711785492504343953926634992332820282019728792003956564819949", 10)
+	num.SetInt64(-121665)
+	den.SetInt64(121666)
+	den.ModInverse(&den, &p)
+	num.Mul(&num, &den)
+	num.Mod(&num, &p)
+	d := edwards25519.D()
+	if d.BigInt().Cmp(&num) != 0 {
+		t.Fatalf("D() = %v != %v", d.BigInt(), &num)
+	}
+
+	// 1/sqrt(-d-1)^2 * (-d-1) == 1
+	inv := edwards25519.InvSqrtMinusDMinusOne()
+	want.Neg(&d)
+	want.Add(&want, &minusOne)
+	fe.Square(&inv)
+	fe.Mul(&fe, &want)
+	if !fe.Equals(want.SetOne()) {
+		t.Fatalf("InvSqrtMinusDMinusOne()^2 * (-d-1) = %v != 1", &fe)
+	}
+
+	// The accessors return copies
+	d[0]++
+	if d2 := edwards25519.D(); d2.Equals(&d) {
+		t.Fatalf("Modifying the result of D() changes the constant")
+	}
+}
+
+func TestFeSequentialSquare(t *testing.T) {
+	var bi, exp big.Int
+	var fe1, fe2, fe3 edwards25519.FieldElement
+	for i := 0; i < 100; i++ {
+		bi.Rand(rnd, &bi25519)
+		fe1.SetBigInt(&bi)
+		n := uint64(i % 20)
+		fe3.Set(&fe1)
+		for j := uint64(0); j < n; j++ {
+			fe3.Square(&fe3)
+		}
+		if !fe2.SequentialSquare(&fe1, n).Equals(&fe3) {
+			t.Fatalf("SequentialSquare(%v, %d) = %v != %v", &bi, n, &fe2, &fe3)
+		}
+		exp.Lsh(big.NewInt(1), uint(n))
+		if fe2.BigInt().Cmp(exp.Exp(&bi, &exp, &bi25519)) != 0 {
+			t.Fatalf("SequentialSquare(%v, %d) = %v != %v", &bi, n, &fe2, &exp)
+		}
+	}
+}
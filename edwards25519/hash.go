@@ -0,0 +1,124 @@
+package edwards25519
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/binary"
+)
+
+// Domain separation string for SharedGenerator().
+const sharedGeneratorDomain = "go-ristretto shared generator"
+
+// Domain separation string for ProvePointFromSeed().
+const pointFromSeedDomain = "go-ristretto point from seed"
+
+// Size of the proof returned by ProvePointFromSeed().
+const PointFromSeedProofSize = 64
+
+// Returns a SHA-256 commitment to the ordered list of points.
+//
+// The number of points is absorbed first, followed by the Ristretto
+// encoding of each point, so that lists of different lengths or in a
+// different order hash to different values.  Requires every point to be
+// even.
+func HashPointVector(points []*ExtendedPoint) [32]byte {
+	var buf [32]byte
+	var ret [32]byte
+	h := sha256.New()
+	binary.BigEndian.PutUint64(buf[:8], uint64(len(points)))
+	h.Write(buf[:8])
+	for _, p := range points {
+		p.RistrettoInto(&buf)
+		h.Write(buf[:])
+	}
+	h.Sum(ret[:0])
+	return ret
+}
+
+// Returns a generator derived from the points a and b, which does not
+// depend on their order: both parties to a protocol obtain the same
+// generator, of which neither knows the discrete logarithm.  Requires a
+// and b to be even.
+func SharedGenerator(a, b *ExtendedPoint) *ExtendedPoint {
+	var aBuf, bBuf [32]byte
+	a.RistrettoInto(&aBuf)
+	b.RistrettoInto(&bBuf)
+	if bytes.Compare(aBuf[:], bBuf[:]) > 0 {
+		aBuf, bBuf = bBuf, aBuf
+	}
+	return new(ExtendedPoint).hashToGroup([]byte(sharedGeneratorDomain),
+		aBuf[:], bBuf[:])
+}
+
+// Derives a point p from seed, of which nobody knows the discrete
+// logarithm, together with a proof that p was derived honestly.
+//
+// The proof consists of the two Elligator preimages r0 || r1 of p, which
+// are the halves of the SHA-512 hash of seed (with a domain separation
+// string), and p is the sum of their Elligator images.  Anyone can check
+// this with VerifyPointFromSeed() without trusting the party that derived
+// p.  The proof contains nothing that cannot be recomputed from seed; it
+// is there so that both steps can be audited separately.
+func ProvePointFromSeed(seed []byte) (p *ExtendedPoint, proof []byte) {
+	var hash [64]byte
+	h := sha512.New()
+	h.Write([]byte(pointFromSeedDomain))
+	h.Write(seed)
+	h.Sum(hash[:0])
+	return new(ExtendedPoint).SetRistrettoHash(&hash), hash[:]
+}
+
+// Returns whether p was derived from seed by ProvePointFromSeed() with
+// the given proof: whether proof is the hash of seed and p is the sum of
+// the Elligator images of its halves.  Requires p to be even.
+func VerifyPointFromSeed(seed []byte, p *ExtendedPoint, proof []byte) bool {
+	var hash [64]byte
+	var q ExtendedPoint
+	if len(proof) != PointFromSeedProofSize {
+		return false
+	}
+	h := sha512.New()
+	h.Write([]byte(pointFromSeedDomain))
+	h.Write(seed)
+	h.Sum(hash[:0])
+	if !bytes.Equal(hash[:], proof) {
+		return false
+	}
+	return p.Equals(q.SetRistrettoHash(&hash))
+}
+
+// Sets p to the hash of the concatenation of the parts to the group:
+// the sum of the Ristretto Elligator images of both halves of its SHA-512
+// digest, as in Point.DeriveDalek().  Returns p.
+func (p *ExtendedPoint) hashToGroup(parts ...[]byte) *ExtendedPoint {
+	var hash [64]byte
+	h := sha512.New()
+	for _, part := range parts {
+		h.Write(part)
+	}
+	h.Sum(hash[:0])
+	return p.SetRistrettoHash(&hash)
+}
+
+// Sets p to the sum of the Ristretto Elligator images of both halves
+// of buf, which is the one-way map from 64 bytes to the group of the
+// ristretto255 RFC.  Returns p.
+//
+// If buf is uniformly random, such as the output of a hash function, then
+// so is p, and nobody knows its discrete logarithm.  This makes it
+// suitable for hashing to the group in protocols like OPRFs and VRFs.
+func (p *ExtendedPoint) SetRistrettoHash(buf *[64]byte) *ExtendedPoint {
+	var half [32]byte
+	var fe FieldElement
+	var cp CompletedPoint
+	var p2 ExtendedPoint
+
+	copy(half[:], buf[:32])
+	fe.SetBytes(&half)
+	p.SetCompleted(cp.SetRistrettoElligator2(&fe))
+	copy(half[:], buf[32:])
+	fe.SetBytes(&half)
+	p2.SetCompleted(cp.SetRistrettoElligator2(&fe))
+	return p.Add(p, &p2)
+}
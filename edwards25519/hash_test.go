@@ -0,0 +1,149 @@
+package edwards25519_test
+
+import (
+	"crypto/sha512"
+	"encoding/hex"
+	"testing"
+
+	"github.com/bwesterb/go-ristretto/edwards25519"
+)
+
+func TestHashPointVector(t *testing.T) {
+	var buf [32]byte
+	var fe edwards25519.FieldElement
+	var cp edwards25519.CompletedPoint
+	points := make([]*edwards25519.ExtendedPoint, 5)
+	for i := range points {
+		rnd.Read(buf[:])
+		fe.SetBytes(&buf)
+		cp.SetRistrettoElligator2(&fe)
+		points[i] = new(edwards25519.ExtendedPoint).SetCompleted(&cp)
+	}
+
+	h := edwards25519.HashPointVector(points)
+	if h != edwards25519.HashPointVector(points) {
+		t.Fatalf("HashPointVector is not deterministic")
+	}
+	if h == edwards25519.HashPointVector(points[:4]) {
+		t.Fatalf("HashPointVector ignores the length")
+	}
+
+	// Reordering changes the hash
+	points[1], points[3] = points[3], points[1]
+	if h == edwards25519.HashPointVector(points) {
+		t.Fatalf("HashPointVector ignores the order")
+	}
+	points[1], points[3] = points[3], points[1]
+
+	// Modifying any point changes the hash
+	for i := range points {
+		orig := *points[i]
+		points[i].Double(points[i])
+		if h == edwards25519.HashPointVector(points) {
+			t.Fatalf("HashPointVector ignores point %d", i)
+		}
+		*points[i] = orig
+	}
+	if h != edwards25519.HashPointVector(points) {
+		t.Fatalf("HashPointVector changed after restoring the points")
+	}
+}
+
+func TestSharedGenerator(t *testing.T) {
+	var buf [32]byte
+	var fe edwards25519.FieldElement
+	var cp edwards25519.CompletedPoint
+	var a, b, c edwards25519.ExtendedPoint
+	for i := 0; i < 10; i++ {
+		for _, p := range []*edwards25519.ExtendedPoint{&a, &b, &c} {
+			rnd.Read(buf[:])
+			fe.SetBytes(&buf)
+			p.SetCompleted(cp.SetRistrettoElligator2(&fe))
+		}
+		g := edwards25519.SharedGenerator(&a, &b)
+		if g.RistrettoEqualsI(edwards25519.SharedGenerator(&a, &b)) != 1 {
+			t.Fatalf("SharedGenerator is not deterministic")
+		}
+		if g.RistrettoEqualsI(edwards25519.SharedGenerator(&b, &a)) != 1 {
+			t.Fatalf("SharedGenerator depends on the order of its arguments")
+		}
+		if g.RistrettoEqualsI(edwards25519.SharedGenerator(&a, &c)) == 1 {
+			t.Fatalf("SharedGenerator ignores its second argument")
+		}
+		if g.RistrettoEqualsI(&a) == 1 || g.RistrettoEqualsI(&b) == 1 {
+			t.Fatalf("SharedGenerator returns one of its arguments")
+		}
+	}
+}
+
+func TestPointFromSeed(t *testing.T) {
+	var other edwards25519.ExtendedPoint
+	seed := []byte("generator 1")
+	p, proof := edwards25519.ProvePointFromSeed(seed)
+	if len(proof) != edwards25519.PointFromSeedProofSize {
+		t.Fatalf("ProvePointFromSeed: proof has length %d", len(proof))
+	}
+	if !edwards25519.VerifyPointFromSeed(seed, p, proof) {
+		t.Fatalf("VerifyPointFromSeed rejects an honest point")
+	}
+	q, _ := edwards25519.ProvePointFromSeed(seed)
+	if !p.Equals(q) {
+		t.Fatalf("ProvePointFromSeed is not deterministic")
+	}
+
+	// A Ristretto-equivalent point is the same group element
+	other.Add(p, new(edwards25519.ExtendedPoint).SetTorsion2())
+	if !edwards25519.VerifyPointFromSeed(seed, &other, proof) {
+		t.Fatalf("VerifyPointFromSeed rejects an equivalent point")
+	}
+
+	// A wrong point, seed or proof is rejected
+	other.Double(p)
+	if edwards25519.VerifyPointFromSeed(seed, &other, proof) {
+		t.Fatalf("VerifyPointFromSeed accepts a wrong point")
+	}
+	if edwards25519.VerifyPointFromSeed([]byte("generator 2"), p, proof) {
+		t.Fatalf("VerifyPointFromSeed accepts a wrong seed")
+	}
+	for _, i := range []int{0, 31, 32, 63} {
+		bad := append([]byte(nil), proof...)
+		bad[i] ^= 1
+		if edwards25519.VerifyPointFromSeed(seed, p, bad) {
+			t.Fatalf("VerifyPointFromSeed accepts a modified proof")
+		}
+	}
+	if edwards25519.VerifyPointFromSeed(seed, p, proof[:32]) {
+		t.Fatalf("VerifyPointFromSeed accepts a short proof")
+	}
+}
+
+// Test vectors for hash-to-group from the ristretto255 RFC, which applies
+// the map to the SHA-512 hash of each label.
+func TestSetRistrettoHash(t *testing.T) {
+	var p edwards25519.ExtendedPoint
+	var buf [32]byte
+	vectors := []struct{ label, encoding string }{
+		{"Ristretto is traditionally a short shot of espresso coffee",
+			"3066f82a1a747d45120d1740f14358531a8f04bbffe6a819f86dfe50f44a0a46"},
+		{"made with the normal amount of ground coffee but extracted with",
+			"f26e5b6f7d362d2d2a94c5d0e7602cb4773c95a2e5c31a64f133189fa76ed61b"},
+		{"about half the amount of water in the same amount of time",
+			"006ccd2a9e6867e6a2c5cea83d3302cc9de128dd2a9a57dd8ee7b9d7ffe02826"},
+		{"by using a finer grind.",
+			"f8f0c87cf237953c5890aec3998169005dae3eca1fbb04548c635953c817f92a"},
+		{"This produces a concentrated shot of coffee per volume.",
+			"ae81e7dedf20a497e10c304a765c1767a42d6e06029758d2d7e8ef7cc4c41179"},
+		{"Just pulling a normal shot short will produce a weaker shot",
+			"e2705652ff9f5e44d3e841bf1c251cf7dddb77d140870d1ab2ed64f1a9ce8628"},
+		{"and is not a Ristretto as some believe.",
+			"80bd07262511cdde4863f8a7434cef696750681cb9510eea557088f76d9e5065"},
+	}
+	for _, v := range vectors {
+		hash := sha512.Sum512([]byte(v.label))
+		p.SetRistrettoHash(&hash).RistrettoInto(&buf)
+		if got := hex.EncodeToString(buf[:]); got != v.encoding {
+			t.Fatalf("SetRistrettoHash(H(%q)) = %s != %s",
+				v.label, got, v.encoding)
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package edwards25519
+
+import (
+	"encoding/binary"
+)
+
+// Domain separation strings for the generators of InnerProductGenerators().
+const (
+	ipGeneratorsG = "go-ristretto inner product G"
+	ipGeneratorsH = "go-ristretto inner product H"
+	ipGeneratorU  = "go-ristretto inner product u"
+)
+
+// Returns the generators for an inner-product argument (as used in
+// Bulletproofs) over vectors of length n: the vectors G and H of n
+// generators each and the extra generator u.
+//
+// The generators are derived deterministically by hashing a domain
+// separation string and their index to the group, so nobody knows a
+// discrete logarithm relation between them.
+func InnerProductGenerators(n int) (G, H []*ExtendedPoint, u *ExtendedPoint) {
+	G = make([]*ExtendedPoint, n)
+	H = make([]*ExtendedPoint, n)
+	for i := 0; i < n; i++ {
+		G[i] = hashToGenerator(ipGeneratorsG, uint64(i))
+		H[i] = hashToGenerator(ipGeneratorsH, uint64(i))
+	}
+	u = hashToGenerator(ipGeneratorU, 0)
+	return
+}
+
+// Returns the point obtained by hashing the domain separation string
+// and index to the group.
+func hashToGenerator(domain string, index uint64) *ExtendedPoint {
+	var buf [8]byte
+	binary.BigEndian.PutUint64(buf[:], index)
+	return new(ExtendedPoint).hashToGroup([]byte(domain), buf[:])
+}
+
+// Sets out[i] to xInv*lo[i] + x*hi[i] for each i, which folds the
+// generator vectors lo and hi in a round of the inner-product argument.
+// Entries of out that are nil are allocated.  out may alias lo or hi.
+//
+// Warning: the running time depends on x and xInv; only use with public
+// challenges.
+func FoldPoints(out, lo, hi []*ExtendedPoint, x, xInv *[32]byte) {
+	var p, tmp ExtendedPoint
+	if len(lo) != len(hi) || len(out) != len(lo) {
+		panic("edwards25519: FoldPoints: vectors must have the same length")
+	}
+	for i := 0; i < len(lo); i++ {
+		p.VarTimeScalarMult(lo[i], xInv)
+		p.Add(&p, tmp.VarTimeScalarMult(hi[i], x))
+		if out[i] == nil {
+			out[i] = new(ExtendedPoint)
+		}
+		out[i].Set(&p)
+	}
+}
+
+// Returns whether commitment is the Pedersen commitment v*G + blinding*H
+// to the value v = sum_i 2^i bits[i] with the given blinding factor,
+// which should be reduced.  Returns false if an entry of bits is not 0 or
+// 1, or if there are more than 252 bits.
+func CheckBitCommitment(commitment *ExtendedPoint, bits []byte,
+	blinding *[32]byte, G, H *ExtendedPoint) bool {
+	var v [32]byte
+	var p, tmp ExtendedPoint
+	if len(bits) > 252 {
+		return false
+	}
+	for i, b := range bits {
+		if b > 1 {
+			return false
+		}
+		v[i/8] |= b << uint(i%8)
+	}
+	p.ScalarMult(G, &v)
+	p.Add(&p, tmp.ScalarMult(H, blinding))
+	return p.RistrettoEqualsI(commitment) == 1
+}
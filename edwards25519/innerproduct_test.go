@@ -0,0 +1,114 @@
+package edwards25519_test
+
+import (
+	"testing"
+
+	"github.com/bwesterb/go-ristretto/edwards25519"
+)
+
+func TestInnerProductGenerators(t *testing.T) {
+	var buf [32]byte
+	G, H, u := edwards25519.InnerProductGenerators(16)
+	if len(G) != 16 || len(H) != 16 {
+		t.Fatalf("InnerProductGenerators(16) returns %d and %d generators",
+			len(G), len(H))
+	}
+
+	// Reproducible, also for other lengths
+	G2, H2, u2 := edwards25519.InnerProductGenerators(8)
+	for i := 0; i < len(G2); i++ {
+		if G[i].RistrettoEqualsI(G2[i]) != 1 || H[i].RistrettoEqualsI(H2[i]) != 1 {
+			t.Fatalf("InnerProductGenerators is not reproducible at %d", i)
+		}
+	}
+	if u.RistrettoEqualsI(u2) != 1 {
+		t.Fatalf("InnerProductGenerators is not reproducible for u")
+	}
+
+	// Independent: no two generators coincide
+	seen := make(map[[32]byte]bool)
+	all := append(append([]*edwards25519.ExtendedPoint{u}, G...), H...)
+	for i, p := range all {
+		p.RistrettoInto(&buf)
+		if seen[buf] {
+			t.Fatalf("Generator %d coincides with an earlier one", i)
+		}
+		seen[buf] = true
+	}
+}
+
+func TestFoldPoints(t *testing.T) {
+	var x, xInv [32]byte
+	var want, tmp edwards25519.ExtendedPoint
+	G, _, _ := edwards25519.InnerProductGenerators(8)
+	lo, hi := G[:4], G[4:]
+	for j := 0; j < 10; j++ {
+		rnd.Read(x[:])
+		rnd.Read(xInv[:])
+		x[31] &= 15
+		xInv[31] &= 15
+
+		out := make([]*edwards25519.ExtendedPoint, 4)
+		edwards25519.FoldPoints(out, lo, hi, &x, &xInv)
+		for i := 0; i < 4; i++ {
+			want.ScalarMult(lo[i], &xInv)
+			want.Add(&want, tmp.ScalarMult(hi[i], &x))
+			if out[i].RistrettoEqualsI(&want) != 1 {
+				t.Fatalf("FoldPoints()[%d] = %v; expected %v", i, out[i], &want)
+			}
+		}
+
+		// Folding in place
+		inPlace := make([]*edwards25519.ExtendedPoint, 4)
+		for i := 0; i < 4; i++ {
+			inPlace[i] = new(edwards25519.ExtendedPoint).Set(lo[i])
+		}
+		edwards25519.FoldPoints(inPlace, inPlace, hi, &x, &xInv)
+		for i := 0; i < 4; i++ {
+			if inPlace[i].RistrettoEqualsI(out[i]) != 1 {
+				t.Fatalf("FoldPoints in place differs at %d", i)
+			}
+		}
+	}
+}
+
+func TestCheckBitCommitment(t *testing.T) {
+	var v, blinding [32]byte
+	var C, tmp edwards25519.ExtendedPoint
+	G, H, _ := edwards25519.InnerProductGenerators(1)
+	for j := 0; j < 10; j++ {
+		rnd.Read(v[:8])
+		rnd.Read(blinding[:])
+		blinding[31] &= 15
+		bits := make([]byte, 64)
+		for i := range bits {
+			bits[i] = (v[i/8] >> uint(i%8)) & 1
+		}
+		C.ScalarMult(G[0], &v)
+		C.Add(&C, tmp.ScalarMult(H[0], &blinding))
+		if !edwards25519.CheckBitCommitment(&C, bits, &blinding, G[0], H[0]) {
+			t.Fatalf("CheckBitCommitment rejects a valid commitment")
+		}
+
+		// Tampered bits, blinding factor or commitment
+		bits[3] ^= 1
+		if edwards25519.CheckBitCommitment(&C, bits, &blinding, G[0], H[0]) {
+			t.Fatalf("CheckBitCommitment accepts a flipped bit")
+		}
+		bits[3] ^= 1
+		bits[5] = 2
+		if edwards25519.CheckBitCommitment(&C, bits, &blinding, G[0], H[0]) {
+			t.Fatalf("CheckBitCommitment accepts a non-bit")
+		}
+		bits[5] = (v[0] >> 5) & 1
+		blinding[0] ^= 1
+		if edwards25519.CheckBitCommitment(&C, bits, &blinding, G[0], H[0]) {
+			t.Fatalf("CheckBitCommitment accepts a wrong blinding factor")
+		}
+		blinding[0] ^= 1
+		C.Add(&C, G[0])
+		if edwards25519.CheckBitCommitment(&C, bits, &blinding, G[0], H[0]) {
+			t.Fatalf("CheckBitCommitment accepts a tampered commitment")
+		}
+	}
+}
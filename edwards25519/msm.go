@@ -0,0 +1,309 @@
+package edwards25519
+
+import (
+	"math"
+)
+
+// Width of the signed windows used by MultiScalarMultStream().
+const msmStreamWindow = 6
+
+// Number of terms from which MultiScalarMult() uses Pippenger's method
+// instead of Straus'.
+const msmPippengerThreshold = 64
+
+// Returns the recommended width of the signed windows for Pippenger's
+// method on n terms.
+//
+// With windows of width w there are about 256/w windows, each of which
+// costs n additions to fill 2^(w-1) buckets and 2^w additions to sum them.
+// This is roughly minimized for w close to ln(n).  The result is between 2
+// and 7, the widths supported by RecodeScalarWindow().
+func PippengerWindowSize(n int) uint {
+	if n < 2 {
+		return 2
+	}
+	w := uint(math.Log(float64(n))) + 1
+	if w < 2 {
+		return 2
+	}
+	if w > 7 {
+		return 7
+	}
+	return w
+}
+
+// Bucket state for Pippenger's method where the terms arrive one by one.
+//
+// For every window position j there are 2^(w-1) buckets; bucket k of
+// window j holds the sum of the points whose j-th signed digit is ±(k+1).
+// As a term only touches its own buckets, they can be filled without
+// knowing the other terms in advance.
+type msmBuckets struct {
+	width   uint
+	windows int
+	buckets []ExtendedPoint
+	digits  []int8
+}
+
+// Prepares b for windows of the given width.
+func (b *msmBuckets) init(width uint) {
+	b.width = width
+	b.windows = int((256 + width - 1) / width)
+	b.buckets = make([]ExtendedPoint, b.windows<<(width-1))
+	b.digits = make([]int8, b.windows)
+	for i := 0; i < len(b.buckets); i++ {
+		b.buckets[i].SetZero()
+	}
+}
+
+// Adds the term s * q to the buckets.
+func (b *msmBuckets) add(s *[32]byte, q *ExtendedPoint) {
+	var t ExtendedPoint
+	RecodeScalarWindow(s, b.width, b.digits)
+	half := 1 << (b.width - 1)
+	for j, d := range b.digits {
+		if d == 0 {
+			continue
+		}
+		if d > 0 {
+			bucket := &b.buckets[j*half+int(d)-1]
+			bucket.Add(bucket, q)
+		} else {
+			bucket := &b.buckets[j*half-int(d)-1]
+			bucket.Add(bucket, t.Neg(q))
+		}
+	}
+}
+
+// Sets p to the sum of the terms added to b.  Returns p.
+func (b *msmBuckets) sumInto(p *ExtendedPoint) *ExtendedPoint {
+	var sum, window ExtendedPoint
+	half := 1 << (b.width - 1)
+	p.SetZero()
+	for j := b.windows - 1; j >= 0; j-- {
+		for i := uint(0); i < b.width; i++ {
+			p.Double(p)
+		}
+
+		// window = sum_k (k+1) * bucket[k] using running sums.
+		sum.SetZero()
+		window.SetZero()
+		for k := half - 1; k >= 0; k-- {
+			sum.Add(&sum, &b.buckets[j*half+k])
+			window.Add(&window, &sum)
+		}
+		p.Add(p, &window)
+	}
+	return p
+}
+
+// Running sum of terms s * q for Pippenger's method, for when the terms
+// arrive over time.  Add a term with Add() and get the sum of the terms
+// added so far with Result().  The zero value is an empty sum.
+//
+// The terms are accumulated into buckets as they arrive, so the memory used
+// does not depend on the number of terms and a call to Result() costs the
+// same regardless of how many terms have been added.
+//
+// Warning: the running time depends on the scalars; do not use with secrets.
+type IncrementalMSM struct {
+	b msmBuckets
+}
+
+// Adds the term scalar * point to the sum.  The scalar and point are not
+// retained.
+func (m *IncrementalMSM) Add(scalar *[32]byte, point *ExtendedPoint) {
+	if m.b.buckets == nil {
+		m.b.init(msmStreamWindow)
+	}
+	m.b.add(scalar, point)
+}
+
+// Returns the sum of the terms added so far.  More terms can be added
+// afterwards.
+func (m *IncrementalMSM) Result() *ExtendedPoint {
+	var ret ExtendedPoint
+	if m.b.buckets == nil {
+		return ret.SetZero()
+	}
+	return m.b.sumInto(&ret)
+}
+
+// Sets out to the sum of s * q over the terms (s, q) returned by next,
+// which is called until it returns ok = false.  Returns out.
+//
+// The terms are accumulated with an IncrementalMSM as they arrive, so the
+// memory used does not depend on the number of terms.  The scalar and point
+// returned by next are not retained and may be reused by the caller.
+//
+// Warning: the running time depends on the scalars; do not use with secrets.
+func MultiScalarMultStream(out *ExtendedPoint,
+	next func() (scalar *[32]byte, point *ExtendedPoint, ok bool)) *ExtendedPoint {
+	var m IncrementalMSM
+	for {
+		s, q, ok := next()
+		if !ok {
+			break
+		}
+		m.Add(s, q)
+	}
+	return out.Set(m.Result())
+}
+
+// Set p to the sum of scalars[i] * points[i].  Returns p.
+//
+// Below 64 terms this uses Straus' method, which interleaves the 5-NAF
+// representations of the scalars so that all terms share the doublings.
+// From 64 terms on, it uses Pippenger's bucket method with windows of
+// width PippengerWindowSize(len(points)).  The scalars should be reduced.
+// An empty sum is zero.  Panics if there are not as many scalars as points.
+//
+// Warning: the running time depends on the scalars; do not use with secrets.
+func (p *ExtendedPoint) MultiScalarMult(points []ExtendedPoint,
+	scalars [][32]byte) *ExtendedPoint {
+	if len(points) != len(scalars) {
+		panic("edwards25519: MultiScalarMult: lengths of points and scalars differ")
+	}
+	if len(points) < msmPippengerThreshold {
+		return p.strausMultiScalarMult(points, scalars)
+	}
+	var b msmBuckets
+	b.init(PippengerWindowSize(len(points)))
+	for i := 0; i < len(points); i++ {
+		b.add(&scalars[i], &points[i])
+	}
+	return b.sumInto(p)
+}
+
+// Returns the sum of weights[i] * points[i] for small weights.
+//
+// Instead of a full scalar multiplication per term, this runs a single
+// double-and-add over the at most 16 bits of the weights, in which all
+// terms share the doublings: it costs one doubling per bit of the largest
+// weight and one addition per set bit of each weight.  For small weights
+// this is much faster than MultiScalarMult().  An empty sum is zero.
+// Panics if there are not as many weights as points.
+//
+// Warning: the running time depends on the weights; do not use with secrets.
+func SmallWeightedSum(points []*ExtendedPoint, weights []uint16) *ExtendedPoint {
+	var pp ProjectivePoint
+	var cp CompletedPoint
+	if len(points) != len(weights) {
+		panic("edwards25519: SmallWeightedSum: lengths of points and weights differ")
+	}
+	var all uint16
+	for _, w := range weights {
+		all |= w
+	}
+	p := new(ExtendedPoint).SetZero()
+	if all == 0 {
+		return p
+	}
+
+	cached := make([]CachedPoint, len(points))
+	for i, q := range points {
+		if weights[i] != 0 {
+			cached[i].SetExtended(q)
+		}
+	}
+	top := 0
+	for all>>uint(top+1) != 0 {
+		top++
+	}
+	for bit := top; bit >= 0; bit-- {
+		pp.SetExtended(p)
+		cp.DoubleProjective(&pp)
+		p.SetCompleted(&cp)
+		for i, w := range weights {
+			if w>>uint(bit)&1 == 1 {
+				p.SetCompleted(cp.AddExtendedCached(p, &cached[i]))
+			}
+		}
+	}
+	return p
+}
+
+// Returns 1 if sum_i aScalars[i] * aPoints[i] and
+// sum_i bScalars[i] * bPoints[i] are Ristretto-equivalent and 0 otherwise.
+// Panics if the number of scalars and points of either sum differ.
+//
+// Instead of computing both sums, this checks whether their difference,
+// computed as a single multi-scalar multiplication, is zero.  Unlike
+// MultiScalarMult(), that multiplication is constant-time, so the scalars
+// may be secret.  They should be reduced, and the points must be even.
+func MSMEqualI(aScalars, bScalars [][32]byte,
+	aPoints, bPoints []*ExtendedPoint) int32 {
+	if len(aScalars) != len(aPoints) || len(bScalars) != len(bPoints) {
+		panic("edwards25519: MSMEqualI: lengths of points and scalars differ")
+	}
+	points := make([]ExtendedPoint, 0, len(aPoints)+len(bPoints))
+	scalars := make([][32]byte, 0, len(aPoints)+len(bPoints))
+	for i := range aPoints {
+		points = append(points, *aPoints[i])
+	}
+	for i := range bPoints {
+		var neg ExtendedPoint
+		points = append(points, *neg.Neg(bPoints[i]))
+	}
+	scalars = append(append(scalars, aScalars...), bScalars...)
+	var diff ExtendedPoint
+	return diff.constantTimeMultiScalarMult(points, scalars).IsIdentityI()
+}
+
+// Set p to the sum of scalars[i] * points[i] in constant time.  Returns p.
+//
+// This is the method of ScalarMultInto() with the doublings shared by all
+// terms, so the scalars should be smaller than 2^253.
+func (p *ExtendedPoint) constantTimeMultiScalarMult(points []ExtendedPoint,
+	scalars [][32]byte) *ExtendedPoint {
+	var t ExtendedPoint
+	luts := make([][17]ExtendedPoint, len(points))
+	windows := make([][51]int8, len(points))
+	for i := range points {
+		precomputeWindowTable(&points[i], &luts[i])
+		computeScalarWindow5(&scalars[i], &windows[i])
+	}
+
+	p.SetZero()
+	for j := 50; j >= 0; j-- {
+		p.DoubleN(p, 5)
+		for i := range points {
+			p.Add(p, t.selectWindow(&luts[i], int32(windows[i][j])))
+		}
+	}
+	return p
+}
+
+// Set p to the sum of scalars[i] * points[i] using Straus' method.
+// Returns p.
+func (p *ExtendedPoint) strausMultiScalarMult(points []ExtendedPoint,
+	scalars [][32]byte) *ExtendedPoint {
+	var pp ProjectivePoint
+	var cp CompletedPoint
+	luts := make([][16]CachedPoint, len(points))
+	nafs := make([][256]int8, len(points))
+	top := -1
+	for i := 0; i < len(points); i++ {
+		precomputeSignedTable(&points[i], &luts[i])
+		computeScalar5NAF(&scalars[i], &nafs[i])
+		for j := 255; j > top; j-- {
+			if nafs[i][j] != 0 {
+				top = j
+				break
+			}
+		}
+	}
+
+	p.SetZero()
+	for j := top; j >= 0; j-- {
+		pp.SetExtended(p)
+		cp.DoubleProjective(&pp)
+		p.SetCompleted(&cp)
+		for i := 0; i < len(points); i++ {
+			if nafs[i][j] != 0 {
+				addNAFDigit(p, &luts[i], nafs[i][j])
+			}
+		}
+	}
+	return p
+}
@@ -0,0 +1,217 @@
+package edwards25519_test
+
+import (
+	"testing"
+
+	"github.com/bwesterb/go-ristretto/edwards25519"
+)
+
+func randomMSMTerms(n int) ([][32]byte, []edwards25519.ExtendedPoint) {
+	var buf [32]byte
+	var fe edwards25519.FieldElement
+	var cp edwards25519.CompletedPoint
+	scalars := make([][32]byte, n)
+	points := make([]edwards25519.ExtendedPoint, n)
+	for i := 0; i < n; i++ {
+		rnd.Read(buf[:])
+		fe.SetBytes(&buf)
+		cp.SetRistrettoElligator2(&fe)
+		points[i].SetCompleted(&cp)
+		rnd.Read(scalars[i][:])
+		scalars[i][31] &= 15
+	}
+	return scalars, points
+}
+
+func TestMultiScalarMultStream(t *testing.T) {
+	var got, want, tmp edwards25519.ExtendedPoint
+	for _, n := range []int{0, 1, 2, 7, 33, 100} {
+		scalars, points := randomMSMTerms(n)
+		want.SetZero()
+		for i := 0; i < n; i++ {
+			want.Add(&want, tmp.ScalarMult(&points[i], &scalars[i]))
+		}
+		i := 0
+		edwards25519.MultiScalarMultStream(&got,
+			func() (*[32]byte, *edwards25519.ExtendedPoint, bool) {
+				if i == n {
+					return nil, nil, false
+				}
+				i++
+				return &scalars[i-1], &points[i-1], true
+			})
+		if got.RistrettoEqualsI(&want) != 1 {
+			t.Fatalf("MultiScalarMultStream() = %v != %v (n=%d)", got, want, n)
+		}
+	}
+}
+
+func TestMultiScalarMult(t *testing.T) {
+	var got, want, tmp edwards25519.ExtendedPoint
+	for _, n := range []int{0, 1, 2, 7, 63, 64, 65, 200} {
+		scalars, points := randomMSMTerms(n)
+		want.SetZero()
+		for i := 0; i < n; i++ {
+			want.Add(&want, tmp.ScalarMult(&points[i], &scalars[i]))
+		}
+		got.MultiScalarMult(points, scalars)
+		if got.RistrettoEqualsI(&want) != 1 {
+			t.Fatalf("MultiScalarMult() = %v != %v (n=%d)", got, want, n)
+		}
+	}
+}
+
+func benchmarkMultiScalarMult(b *testing.B, n int) {
+	var p edwards25519.ExtendedPoint
+	scalars, points := randomMSMTerms(n)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		p.MultiScalarMult(points, scalars)
+	}
+}
+
+func BenchmarkMultiScalarMult1(b *testing.B)    { benchmarkMultiScalarMult(b, 1) }
+func BenchmarkMultiScalarMult16(b *testing.B)   { benchmarkMultiScalarMult(b, 16) }
+func BenchmarkMultiScalarMult128(b *testing.B)  { benchmarkMultiScalarMult(b, 128) }
+func BenchmarkMultiScalarMult1024(b *testing.B) { benchmarkMultiScalarMult(b, 1024) }
+
+func TestPippengerWindowSize(t *testing.T) {
+	prev := uint(0)
+	for n := 0; n < 1<<20; n = n*2 + 1 {
+		w := edwards25519.PippengerWindowSize(n)
+		if w < 2 || w > 7 {
+			t.Fatalf("PippengerWindowSize(%d) = %d is out of range", n, w)
+		}
+		if w < prev {
+			t.Fatalf("PippengerWindowSize(%d) = %d < %d", n, w, prev)
+		}
+		prev = w
+	}
+	if edwards25519.PippengerWindowSize(1<<20) != 7 {
+		t.Fatalf("PippengerWindowSize(2^20) != 7")
+	}
+}
+
+func TestIncrementalMSM(t *testing.T) {
+	var m edwards25519.IncrementalMSM
+	var want, tmp edwards25519.ExtendedPoint
+	scalars, points := randomMSMTerms(50)
+	want.SetZero()
+	if m.Result().RistrettoEqualsI(&want) != 1 {
+		t.Fatalf("IncrementalMSM{}.Result() != 0")
+	}
+	for i := 0; i < len(scalars); i++ {
+		m.Add(&scalars[i], &points[i])
+		want.Add(&want, tmp.ScalarMult(&points[i], &scalars[i]))
+		if i%7 != 0 && i != len(scalars)-1 {
+			continue
+		}
+		if got := m.Result(); got.RistrettoEqualsI(&want) != 1 {
+			t.Fatalf("Result() after %d terms = %v != %v", i+1, got, want)
+		}
+	}
+}
+
+func TestSmallWeightedSum(t *testing.T) {
+	var want, tmp edwards25519.ExtendedPoint
+	var s [32]byte
+	for _, n := range []int{0, 1, 2, 7, 50} {
+		_, points := randomMSMTerms(n)
+		ptrs := make([]*edwards25519.ExtendedPoint, n)
+		weights := make([]uint16, n)
+		want.SetZero()
+		for i := 0; i < n; i++ {
+			ptrs[i] = &points[i]
+			switch i % 4 {
+			case 0:
+				weights[i] = uint16(rnd.Intn(1 << 16))
+			case 1:
+				weights[i] = uint16(rnd.Intn(8))
+			case 2:
+				weights[i] = 0xffff
+			}
+			s = [32]byte{byte(weights[i]), byte(weights[i] >> 8)}
+			want.Add(&want, tmp.ScalarMult(&points[i], &s))
+		}
+		if got := edwards25519.SmallWeightedSum(ptrs, weights); !got.Equals(&want) {
+			t.Fatalf("SmallWeightedSum(%d terms) = %v != %v", n, got, &want)
+		}
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatalf("SmallWeightedSum does not panic on a length mismatch")
+			}
+		}()
+		_, points := randomMSMTerms(2)
+		edwards25519.SmallWeightedSum(
+			[]*edwards25519.ExtendedPoint{&points[0], &points[1]},
+			[]uint16{1})
+	}()
+}
+
+func BenchmarkSmallWeightedSum(b *testing.B) {
+	_, points := randomMSMTerms(128)
+	ptrs := make([]*edwards25519.ExtendedPoint, len(points))
+	weights := make([]uint16, len(points))
+	for i := range points {
+		ptrs[i] = &points[i]
+		weights[i] = uint16(rnd.Intn(16))
+	}
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		edwards25519.SmallWeightedSum(ptrs, weights)
+	}
+}
+
+func TestMSMEqualI(t *testing.T) {
+	var sum edwards25519.ExtendedPoint
+	for _, n := range []int{0, 1, 5} {
+		scalars, points := randomMSMTerms(n)
+		ptrs := make([]*edwards25519.ExtendedPoint, n)
+		for i := range points {
+			ptrs[i] = &points[i]
+		}
+
+		// The same terms in reverse order, shifted by torsion
+		revScalars := make([][32]byte, n)
+		revPtrs := make([]*edwards25519.ExtendedPoint, n)
+		torsion := new(edwards25519.ExtendedPoint).SetTorsion2()
+		for i := range points {
+			revScalars[n-1-i] = scalars[i]
+			revPtrs[n-1-i] = new(edwards25519.ExtendedPoint).Add(&points[i], torsion)
+		}
+		if edwards25519.MSMEqualI(scalars, revScalars, ptrs, revPtrs) != 1 {
+			t.Fatalf("MSMEqualI: reordered sums differ")
+		}
+
+		// The single term MultiScalarMult() * 1
+		sum.MultiScalarMult(points, scalars)
+		one := [][32]byte{{1}}
+		sumPtr := []*edwards25519.ExtendedPoint{&sum}
+		if edwards25519.MSMEqualI(scalars, one, ptrs, sumPtr) != 1 {
+			t.Fatalf("MSMEqualI: sum differs from MultiScalarMult()")
+		}
+
+		if n == 0 {
+			continue
+		}
+		scalars[0][0] ^= 1
+		if edwards25519.MSMEqualI(scalars, revScalars, ptrs, revPtrs) != 0 {
+			t.Fatalf("MSMEqualI: different sums are equal")
+		}
+		if edwards25519.MSMEqualI(scalars, one, ptrs, sumPtr) != 0 {
+			t.Fatalf("MSMEqualI: different sums are equal")
+		}
+	}
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Fatalf("MSMEqualI does not panic on a length mismatch")
+			}
+		}()
+		edwards25519.MSMEqualI([][32]byte{{1}}, nil, nil, nil)
+	}()
+}
@@ -0,0 +1,31 @@
+// +build opcount
+
+package edwards25519
+
+import (
+	"sync/atomic"
+)
+
+// Tallies of field operations, kept when built with the opcount tag.
+var opCounters OpCounts
+
+func countMul()     { atomic.AddUint64(&opCounters.Mul, 1) }
+func countSquare()  { atomic.AddUint64(&opCounters.Square, 1) }
+func countInverse() { atomic.AddUint64(&opCounters.Inverse, 1) }
+
+// Resets the tallies returned by OpCounters() to zero.
+func ResetOpCounters() {
+	atomic.StoreUint64(&opCounters.Mul, 0)
+	atomic.StoreUint64(&opCounters.Square, 0)
+	atomic.StoreUint64(&opCounters.Inverse, 0)
+}
+
+// Returns the number of field operations performed since the last call to
+// ResetOpCounters().
+func OpCounters() OpCounts {
+	return OpCounts{
+		Mul:     atomic.LoadUint64(&opCounters.Mul),
+		Square:  atomic.LoadUint64(&opCounters.Square),
+		Inverse: atomic.LoadUint64(&opCounters.Inverse),
+	}
+}
@@ -0,0 +1,19 @@
+// +build !opcount
+
+package edwards25519
+
+// Without the opcount tag, the counting functions are empty and are
+// inlined away.
+
+func countMul()     {}
+func countSquare()  {}
+func countInverse() {}
+
+// Does nothing: build with the opcount tag to count field operations.
+func ResetOpCounters() {}
+
+// Returns zero tallies: build with the opcount tag to count field
+// operations.
+func OpCounters() OpCounts {
+	return OpCounts{}
+}
@@ -0,0 +1,35 @@
+// +build opcount
+
+package edwards25519_test
+
+import (
+	"testing"
+
+	"github.com/bwesterb/go-ristretto/edwards25519"
+)
+
+func TestOpCounters(t *testing.T) {
+	var p, q, r edwards25519.ExtendedPoint
+	var fe edwards25519.FieldElement
+	q.SetBase()
+	r.SetBase()
+
+	// Add computes a CompletedPoint with 5 multiplications and converts
+	// it back to extended coordinates with 4 more.
+	edwards25519.ResetOpCounters()
+	p.Add(&q, &r)
+	if c := edwards25519.OpCounters(); c.Mul != 9 || c.Square != 0 || c.Inverse != 0 {
+		t.Fatalf("OpCounters() after Add = %+v; expected 9 multiplications", c)
+	}
+
+	edwards25519.ResetOpCounters()
+	fe.Inverse(&q.X)
+	if c := edwards25519.OpCounters(); c.Inverse != 1 || c.Mul == 0 || c.Square == 0 {
+		t.Fatalf("OpCounters() after Inverse = %+v", c)
+	}
+
+	edwards25519.ResetOpCounters()
+	if c := edwards25519.OpCounters(); c != (edwards25519.OpCounts{}) {
+		t.Fatalf("OpCounters() after ResetOpCounters() = %+v", c)
+	}
+}
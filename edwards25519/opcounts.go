@@ -0,0 +1,15 @@
+package edwards25519
+
+// Number of field operations performed, as returned by OpCounters().
+//
+// The operations are only counted when the package is built with the
+// opcount tag, for instance with go test -tags opcount.  Otherwise the
+// counters cost nothing and OpCounters() always returns zeroes.
+//
+// Mul counts FieldElement.Mul, Square counts FieldElement.Square and
+// DoubledSquare, and Inverse counts FieldElement.Inverse; the
+// multiplications and squarings that an inversion performs internally
+// are counted as well.
+type OpCounts struct {
+	Mul, Square, Inverse uint64
+}
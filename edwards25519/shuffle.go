@@ -0,0 +1,45 @@
+package edwards25519
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Returns the Pedersen commitments perm[i]*G + blindings[i]*H to the
+// entries of the permutation perm of 0, ..., n-1, as used by the
+// arguments of a verifiable shuffle.  The blinding factors should be
+// reduced and kept secret.  Returns an error if perm is not a permutation
+// or if there are not as many blinding factors as entries.
+//
+// The commitments are computed with constant-time scalar multiplications,
+// so their running time does not depend on the permutation or on the
+// blinding factors.  However, checking that perm is a permutation branches
+// on its entries and indexes memory with them, so that check does leak
+// information about perm through timing.
+func CommitPermutation(perm []int, blindings [][32]byte,
+	G, H *ExtendedPoint) ([]*ExtendedPoint, error) {
+	var v [32]byte
+	var tmp ExtendedPoint
+	if len(perm) != len(blindings) {
+		return nil, fmt.Errorf("Got %d blinding factors for %d entries",
+			len(blindings), len(perm))
+	}
+	seen := make([]bool, len(perm))
+	for i, j := range perm {
+		if j < 0 || j >= len(perm) {
+			return nil, fmt.Errorf("Entry %d of the permutation is out of range", i)
+		}
+		if seen[j] {
+			return nil, fmt.Errorf("Entry %d of the permutation is repeated", i)
+		}
+		seen[j] = true
+	}
+
+	ret := make([]*ExtendedPoint, len(perm))
+	for i, j := range perm {
+		binary.LittleEndian.PutUint64(v[:8], uint64(j))
+		ret[i] = new(ExtendedPoint).ScalarMult(G, &v)
+		ret[i].Add(ret[i], tmp.ScalarMult(H, &blindings[i]))
+	}
+	return ret, nil
+}
@@ -0,0 +1,50 @@
+package edwards25519_test
+
+import (
+	"testing"
+
+	"github.com/bwesterb/go-ristretto/edwards25519"
+)
+
+func TestCommitPermutation(t *testing.T) {
+	var G, H, want, tmp edwards25519.ExtendedPoint
+	var v [32]byte
+	G.SetBase()
+	H.SetRistrettoHash(&[64]byte{1})
+	for _, n := range []int{0, 1, 5} {
+		perm := rnd.Perm(n)
+		blindings := make([][32]byte, n)
+		for i := range blindings {
+			rnd.Read(blindings[i][:])
+			blindings[i][31] &= 15
+		}
+		commitments, err := edwards25519.CommitPermutation(perm, blindings, &G, &H)
+		if err != nil {
+			t.Fatalf("CommitPermutation(%v): %v", perm, err)
+		}
+		if len(commitments) != n {
+			t.Fatalf("CommitPermutation returned %d commitments", len(commitments))
+		}
+		for i := range perm {
+			v = [32]byte{byte(perm[i])}
+			want.ScalarMult(&G, &v)
+			want.Add(&want, tmp.ScalarMult(&H, &blindings[i]))
+			if !commitments[i].Equals(&want) {
+				t.Fatalf("CommitPermutation: commitment %d = %v != %v",
+					i, commitments[i], &want)
+			}
+		}
+	}
+
+	blindings := make([][32]byte, 3)
+	for _, perm := range [][]int{
+		{0, 1, 1},
+		{0, 1, 3},
+		{-1, 0, 1},
+		{0, 1},
+	} {
+		if _, err := edwards25519.CommitPermutation(perm, blindings, &G, &H); err == nil {
+			t.Fatalf("CommitPermutation accepts %v", perm)
+		}
+	}
+}
@@ -1,5 +1,13 @@
 package edwards25519
 
+import (
+	"runtime"
+	"sync"
+)
+
+// Number of private keys DerivePublicKeys() hands to a goroutine at once.
+const derivePublicKeysChunk = 64
+
 type NielsPoint struct {
 	YPlusX, YMinusX, XY2D FieldElement
 }
@@ -159,6 +167,58 @@ func (t *ScalarMultTable) ScalarMult(p *ExtendedPoint, s *[32]byte) {
 	}
 }
 
+// Set p to s * B, where B is the basepoint set by SetBase().  Returns p.
+//
+// This uses the precomputed BaseScalarMultTable and is several times
+// faster than p.ScalarMult(B, s).  Like ScalarMult(), it is constant-time.
+func (p *ExtendedPoint) ScalarMultBase(s *[32]byte) *ExtendedPoint {
+	BaseScalarMultTable.ScalarMult(p, s)
+	return p
+}
+
+// Returns privs[i] * B for every i, where B is the basepoint set by
+// SetBase().  Like ScalarMultBase(), this is constant-time.
+//
+// All derivations share the precomputed BaseScalarMultTable, just as a
+// loop of ScalarMultBase() does.  The speedup over such a loop comes from
+// spreading the work over up to GOMAXPROCS goroutines, which happens for
+// more than 64 keys.
+func DerivePublicKeys(privs [][32]byte) []*ExtendedPoint {
+	ret := make([]*ExtendedPoint, len(privs))
+	derive := func(start, end int) {
+		for i := start; i < end; i++ {
+			ret[i] = new(ExtendedPoint).ScalarMultBase(&privs[i])
+		}
+	}
+
+	workers := runtime.GOMAXPROCS(0)
+	if workers == 1 || len(privs) <= derivePublicKeysChunk {
+		derive(0, len(privs))
+		return ret
+	}
+	chunks := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for start := range chunks {
+				end := start + derivePublicKeysChunk
+				if end > len(privs) {
+					end = len(privs)
+				}
+				derive(start, end)
+			}
+		}()
+	}
+	for start := 0; start < len(privs); start += derivePublicKeysChunk {
+		chunks <- start
+	}
+	close(chunks)
+	wg.Wait()
+	return ret
+}
+
 func (t *ScalarMultTable) VarTimeScalarMult(p *ExtendedPoint, s *[32]byte) {
 	var w [64]int8
 	computeScalarWindow4(s, &w)
@@ -162,3 +162,78 @@ func BenchmarkScalarMultTableVarTimeScalarMult(b *testing.B) {
 		table.VarTimeScalarMult(&ep, &sBuf)
 	}
 }
+
+func TestScalarMultBase(t *testing.T) {
+	var B, p1, p2 edwards25519.ExtendedPoint
+	var s [32]byte
+	B.SetBase()
+	for i := 0; i < 1000; i++ {
+		rnd.Read(s[:])
+		s[31] &= 15
+		p1.ScalarMult(&B, &s)
+		p2.ScalarMultBase(&s)
+		if edwardsEncoding(&p1) != edwardsEncoding(&p2) {
+			t.Fatalf("ScalarMultBase(%x) = %v != %v", s, &p2, &p1)
+		}
+	}
+}
+
+func BenchmarkScalarMultBase(b *testing.B) {
+	var p edwards25519.ExtendedPoint
+	var s [32]byte
+	rnd.Read(s[:])
+	s[31] &= 15
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		p.ScalarMultBase(&s)
+	}
+}
+
+func TestDerivePublicKeys(t *testing.T) {
+	var want edwards25519.ExtendedPoint
+	for _, n := range []int{0, 1, 64, 65, 300} {
+		privs := make([][32]byte, n)
+		for i := range privs {
+			rnd.Read(privs[i][:])
+			privs[i][31] &= 15
+		}
+		pubs := edwards25519.DerivePublicKeys(privs)
+		if len(pubs) != n {
+			t.Fatalf("DerivePublicKeys returned %d keys for %d", len(pubs), n)
+		}
+		for i := range privs {
+			want.ScalarMultBase(&privs[i])
+			if !pubs[i].Equals(&want) {
+				t.Fatalf("DerivePublicKeys: key %d = %v != %v", i, pubs[i], &want)
+			}
+		}
+	}
+}
+
+func benchmarkPrivateKeys(n int) [][32]byte {
+	privs := make([][32]byte, n)
+	for i := range privs {
+		rnd.Read(privs[i][:])
+		privs[i][31] &= 15
+	}
+	return privs
+}
+
+func BenchmarkDerivePublicKeys256(b *testing.B) {
+	privs := benchmarkPrivateKeys(256)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		edwards25519.DerivePublicKeys(privs)
+	}
+}
+
+func BenchmarkScalarMultBaseLoop256(b *testing.B) {
+	privs := benchmarkPrivateKeys(256)
+	pubs := make([]edwards25519.ExtendedPoint, len(privs))
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		for i := range privs {
+			pubs[i].ScalarMultBase(&privs[i])
+		}
+	}
+}
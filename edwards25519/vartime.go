@@ -1,5 +1,16 @@
 package edwards25519
 
+import (
+	"sync"
+)
+
+// Odd multiples of the basepoint for DoubleScalarMultBaseVartime(),
+// computed on first use.
+var (
+	baseSignedTable     [16]CachedPoint
+	baseSignedTableOnce sync.Once
+)
+
 func load8u(in []byte) uint64 {
 	var r uint64
 	r = uint64(in[0])
@@ -50,16 +61,42 @@ func computeScalar5NAF(s *[32]byte, naf *[256]int8) {
 	}
 }
 
-func (p *ExtendedPoint) VarTimeScalarMult(q *ExtendedPoint, s *[32]byte) *ExtendedPoint {
-	var lut [8]ExtendedPoint
+// Returns the table q, 3q, 5q, ..., 15q, -q, -3q, ..., -15q of the odd
+// multiples of q and their negations, as used for 5-NAF scalar
+// multiplication.  With the negations at hand, a negative digit costs
+// the same as a positive one.
+func PrecomputeSignedTable(q *ExtendedPoint) []CachedPoint {
+	var table [16]CachedPoint
+	precomputeSignedTable(q, &table)
+	return table[:]
+}
 
-	// Precomputations
-	var dblQ ExtendedPoint
+// Fills table with the odd multiples of q and their negations.
+// See PrecomputeSignedTable().
+func precomputeSignedTable(q *ExtendedPoint, table *[16]CachedPoint) {
+	var dblQ, multiple ExtendedPoint
+	var cp CompletedPoint
 	dblQ.Double(q)
-	lut[0].Set(q)
+	table[0].SetExtended(q)
+	table[8].Neg(&table[0])
 	for i := 1; i < 8; i++ {
-		lut[i].Add(&lut[i-1], &dblQ)
+		multiple.SetCompleted(cp.AddExtendedCached(&dblQ, &table[i-1]))
+		table[i].SetExtended(&multiple)
+		table[i+8].Neg(&table[i])
 	}
+}
+
+// Set p to s * q.  Returns p.
+//
+// This uses the signed width-5 non-adjacent form of s and skips the
+// leading zero digits, which makes it faster than ScalarMult(), especially
+// for short scalars.  The result is equal to that of ScalarMult(), although
+// possibly with a different representation.
+//
+// Warning: the running time depends on s; do not use with secret scalars.
+func (p *ExtendedPoint) VarTimeScalarMult(q *ExtendedPoint, s *[32]byte) *ExtendedPoint {
+	var lut [16]CachedPoint
+	precomputeSignedTable(q, &lut)
 
 	// Compute non-adjacent form of s
 	var naf [256]int8
@@ -83,9 +120,9 @@ func (p *ExtendedPoint) VarTimeScalarMult(q *ExtendedPoint, s *[32]byte) *Extend
 	var cp CompletedPoint
 	for {
 		if naf[i] > 0 {
-			cp.AddExtended(p, &lut[(naf[i]+1)/2-1])
+			cp.AddExtendedCached(p, &lut[(naf[i]-1)/2])
 		} else {
-			cp.SubExtended(p, &lut[(1-naf[i])/2-1])
+			cp.AddExtendedCached(p, &lut[8+(-naf[i]-1)/2])
 		}
 
 		if i == 0 {
@@ -115,3 +152,63 @@ func (p *ExtendedPoint) VarTimeScalarMult(q *ExtendedPoint, s *[32]byte) *Extend
 
 	return p
 }
+
+// Set p to a * B + b * q, where B is the basepoint set by SetBase().
+// Returns p.
+//
+// This is the combination s*B - c*A that comes up in signature
+// verification.  It interleaves the 5-NAF representations of a and b
+// (Straus' method), so the doublings are shared, and uses a precomputed
+// table for B.  It is considerably faster than two separate scalar
+// multiplications.
+//
+// Warning: the running time depends on a and b; do not use with secret
+// scalars.
+func (p *ExtendedPoint) DoubleScalarMultBaseVartime(a, b *[32]byte,
+	q *ExtendedPoint) *ExtendedPoint {
+	var lutQ [16]CachedPoint
+	var nafA, nafB [256]int8
+	var pp ProjectivePoint
+	var cp CompletedPoint
+	baseSignedTableOnce.Do(func() {
+		var B ExtendedPoint
+		precomputeSignedTable(B.SetBase(), &baseSignedTable)
+	})
+	precomputeSignedTable(q, &lutQ)
+	computeScalar5NAF(a, &nafA)
+	computeScalar5NAF(b, &nafB)
+
+	// Skip the leading zeroes of both
+	i := 255
+	for ; i >= 0; i-- {
+		if nafA[i] != 0 || nafB[i] != 0 {
+			break
+		}
+	}
+
+	p.SetZero()
+	for ; i >= 0; i-- {
+		pp.SetExtended(p)
+		cp.DoubleProjective(&pp)
+		p.SetCompleted(&cp)
+		if nafA[i] != 0 {
+			addNAFDigit(p, &baseSignedTable, nafA[i])
+		}
+		if nafB[i] != 0 {
+			addNAFDigit(p, &lutQ, nafB[i])
+		}
+	}
+	return p
+}
+
+// Sets p to p + d*q, where lut is the table of q computed by
+// precomputeSignedTable() and d is an odd digit of a 5-NAF.
+func addNAFDigit(p *ExtendedPoint, lut *[16]CachedPoint, d int8) {
+	var cp CompletedPoint
+	if d > 0 {
+		cp.AddExtendedCached(p, &lut[(d-1)/2])
+	} else {
+		cp.AddExtendedCached(p, &lut[8+(-d-1)/2])
+	}
+	p.SetCompleted(&cp)
+}
@@ -73,3 +73,115 @@ func BenchmarkVarTimeScalarMult(b *testing.B) {
 		ep.VarTimeScalarMult(&ep, &s)
 	}
 }
+
+func TestVarTimeScalarMultShort(t *testing.T) {
+	rnd := rand.New(rand.NewSource(37))
+	var fe FieldElement
+	var cp CompletedPoint
+	var q, p1, p2 ExtendedPoint
+	var buf, s [32]byte
+	for i := 0; i < 1000; i++ {
+		rnd.Read(buf[:])
+		fe.SetBytes(&buf)
+		q.SetCompleted(cp.SetRistrettoElligator2(&fe))
+		rnd.Read(s[:])
+		s[31] &= 15
+		if i%10 == 0 {
+			// Short scalars with many leading zero digits
+			for j := 8; j < 32; j++ {
+				s[j] = 0
+			}
+		}
+		p1.ScalarMult(&q, &s)
+		p2.VarTimeScalarMult(&q, &s)
+		if p1.RistrettoEqualsI(&p2) != 1 {
+			t.Fatalf("VarTimeScalarMult([%x]%v) = %v != %v", s, &q, &p2, &p1)
+		}
+	}
+}
+
+func TestDoubleScalarMultBaseVartime(t *testing.T) {
+	rnd := rand.New(rand.NewSource(37))
+	var fe FieldElement
+	var cp CompletedPoint
+	var B, q, p1, p2, tmp ExtendedPoint
+	var buf, a, b [32]byte
+	B.SetBase()
+	for i := 0; i < 1000; i++ {
+		rnd.Read(buf[:])
+		fe.SetBytes(&buf)
+		q.SetCompleted(cp.SetRistrettoElligator2(&fe))
+		rnd.Read(a[:])
+		rnd.Read(b[:])
+		a[31] &= 15
+		b[31] &= 15
+		switch i % 10 {
+		case 0:
+			a = [32]byte{}
+		case 1:
+			b = [32]byte{}
+		case 2:
+			for j := 4; j < 32; j++ {
+				a[j] = 0
+			}
+		}
+		p1.ScalarMult(&B, &a)
+		p1.Add(&p1, tmp.ScalarMult(&q, &b))
+		p2.DoubleScalarMultBaseVartime(&a, &b, &q)
+		if p1.RistrettoEqualsI(&p2) != 1 {
+			t.Fatalf("[%x]B + [%x]%v = %v != %v", a, b, &q, &p2, &p1)
+		}
+	}
+}
+
+func BenchmarkDoubleScalarMultBaseVartime(b *testing.B) {
+	rnd := rand.New(rand.NewSource(37))
+	var buf, s1, s2 [32]byte
+	var cp CompletedPoint
+	var ep ExtendedPoint
+	var fe FieldElement
+	rnd.Read(s1[:])
+	rnd.Read(s2[:])
+	s1[31] &= 15
+	s2[31] &= 15
+	rnd.Read(buf[:])
+	fe.SetBytes(&buf)
+	ep.SetCompleted(cp.SetRistrettoElligator2(&fe))
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		ep.DoubleScalarMultBaseVartime(&s1, &s2, &ep)
+	}
+}
+
+func TestPrecomputeSignedTable(t *testing.T) {
+	rnd := rand.New(rand.NewSource(37))
+	var fe FieldElement
+	var cp CompletedPoint
+	var q, zero, got, want ExtendedPoint
+	var s [32]byte
+	zero.SetZero()
+	for i := 0; i < 100; i++ {
+		var buf [32]byte
+		rnd.Read(buf[:])
+		fe.SetBytes(&buf)
+		cp.SetRistrettoElligator2(&fe)
+		q.SetCompleted(&cp)
+		table := PrecomputeSignedTable(&q)
+		if len(table) != 16 {
+			t.Fatalf("PrecomputeSignedTable() has %d entries", len(table))
+		}
+		for j := 0; j < 8; j++ {
+			s[0] = byte(2*j + 1)
+			want.ScalarMult(&q, &s)
+			got.SetCompleted(cp.AddExtendedCached(&zero, &table[j]))
+			if got.RistrettoEqualsI(&want) != 1 {
+				t.Fatalf("table[%d] = %v != %v", j, got, want)
+			}
+			want.Neg(&want)
+			got.SetCompleted(cp.AddExtendedCached(&zero, &table[j+8]))
+			if got.RistrettoEqualsI(&want) != 1 {
+				t.Fatalf("table[%d] = %v != %v", j+8, got, want)
+			}
+		}
+	}
+}
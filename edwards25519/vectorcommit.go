@@ -0,0 +1,30 @@
+package edwards25519
+
+import (
+	"encoding/binary"
+)
+
+// Domain separation string for the generators of VectorCommit().
+const vectorCommitDomain = "go-ristretto vector commitment"
+
+// Returns the commitment sum_i values[i] * G_i to the vector values, where
+// G_i is derived by hashing domain and i to the group.  The values should
+// be reduced.
+//
+// As nobody knows a discrete logarithm relation between the generators,
+// the commitment is binding.  It is not hiding: to hide the values, add a
+// random multiple of a generator from another domain.  Commitments with
+// the same domain are homomorphic: the sum of the commitments to two
+// vectors is the commitment to their sum modulo the group order.
+//
+// Warning: this uses MultiScalarMult(), whose running time depends on the
+// values.
+func VectorCommit(values [][32]byte, domain []byte) *ExtendedPoint {
+	var buf [8]byte
+	generators := make([]ExtendedPoint, len(values))
+	for i := range values {
+		binary.BigEndian.PutUint64(buf[:], uint64(i))
+		generators[i].hashToGroup([]byte(vectorCommitDomain), domain, buf[:])
+	}
+	return new(ExtendedPoint).MultiScalarMult(generators, values)
+}
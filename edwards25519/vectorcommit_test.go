@@ -0,0 +1,54 @@
+package edwards25519_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/bwesterb/go-ristretto/edwards25519"
+)
+
+// Returns n random reduced scalars, encoded and as big.Ints.
+func randomVector(n int) ([][32]byte, []big.Int) {
+	ret := make([][32]byte, n)
+	bis := make([]big.Int, n)
+	for i := range ret {
+		bis[i].Rand(rnd, &biL)
+		ret[i] = leBytes(&bis[i])
+	}
+	return ret, bis
+}
+
+func TestVectorCommit(t *testing.T) {
+	var want edwards25519.ExtendedPoint
+	domain := []byte("test")
+	for _, n := range []int{0, 1, 10} {
+		a, aBi := randomVector(n)
+		b, bBi := randomVector(n)
+		ca := edwards25519.VectorCommit(a, domain)
+		if !ca.Equals(edwards25519.VectorCommit(a, domain)) {
+			t.Fatalf("VectorCommit is not deterministic")
+		}
+
+		// Commitments to a and b add up to the commitment to a + b
+		sum := make([][32]byte, n)
+		for i := range sum {
+			var x big.Int
+			sum[i] = leBytes(x.Add(&aBi[i], &bBi[i]))
+		}
+		want.Add(ca, edwards25519.VectorCommit(b, domain))
+		if !edwards25519.VectorCommit(sum, domain).Equals(&want) {
+			t.Fatalf("VectorCommit is not homomorphic")
+		}
+
+		if n == 0 {
+			continue
+		}
+		if ca.Equals(edwards25519.VectorCommit(a, []byte("other"))) {
+			t.Fatalf("VectorCommit ignores the domain")
+		}
+		a[0], a[n-1] = a[n-1], a[0]
+		if n > 1 && ca.Equals(edwards25519.VectorCommit(a, domain)) {
+			t.Fatalf("VectorCommit ignores the positions")
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package edwards25519
+
+import (
+	"encoding/binary"
+)
+
+// Returns the Feldman commitments c_i * B to the coefficients c_i of a
+// secret-sharing polynomial f(x) = sum_i c_i x^i, where B is the basepoint.
+// The coefficients should be reduced.  See FeldmanVerifyShare().
+func FeldmanCommit(coeffs [][32]byte) []*ExtendedPoint {
+	ret := make([]*ExtendedPoint, len(coeffs))
+	for i := range coeffs {
+		ret[i] = new(ExtendedPoint).ScalarMultBase(&coeffs[i])
+	}
+	return ret
+}
+
+// Returns whether share = f(index) for the polynomial f to which
+// commitments were created with FeldmanCommit(), that is, whether
+// share * B == sum_i index^i * commitments[i].  Returns false for index 0,
+// as f(0) is the secret itself.
+//
+// The right-hand side is evaluated with Horner's rule instead of with a
+// multi-scalar multiplication by the powers of index.  Horner's rule only
+// needs multiplications by the small scalar index, which is cheaper.
+func FeldmanVerifyShare(commitments []*ExtendedPoint, index uint32,
+	share *[32]byte) bool {
+	var lhs, rhs ExtendedPoint
+	var x [32]byte
+	if index == 0 {
+		return false
+	}
+	binary.LittleEndian.PutUint32(x[:], index)
+	rhs.SetZero()
+	for i := len(commitments) - 1; i >= 0; i-- {
+		rhs.VarTimeScalarMult(&rhs, &x)
+		rhs.Add(&rhs, commitments[i])
+	}
+	lhs.ScalarMultBase(share)
+	return lhs.Equals(&rhs)
+}
@@ -0,0 +1,62 @@
+package edwards25519_test
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/bwesterb/go-ristretto/edwards25519"
+)
+
+// Returns the little-endian encoding of x, which must be below 2^256.
+func leBytes(x *big.Int) [32]byte {
+	var ret [32]byte
+	be := x.Bytes()
+	for i := range be {
+		ret[i] = be[len(be)-1-i]
+	}
+	return ret
+}
+
+func TestFeldmanVSS(t *testing.T) {
+	var l, bi, share, power big.Int
+	l.SetString("7237005577332262213973186563042994240857116359379907606001950938285454250989", 10)
+
+	// A random polynomial of degree 2
+	coeffs := make([][32]byte, 3)
+	bigCoeffs := make([]big.Int, 3)
+	for i := range coeffs {
+		bigCoeffs[i].Rand(rnd, &l)
+		coeffs[i] = leBytes(&bigCoeffs[i])
+	}
+	commitments := edwards25519.FeldmanCommit(coeffs)
+	if len(commitments) != 3 {
+		t.Fatalf("len(FeldmanCommit()) = %d", len(commitments))
+	}
+
+	// The secret f(0) is never a valid share
+	if edwards25519.FeldmanVerifyShare(commitments, 0, &coeffs[0]) {
+		t.Fatalf("Share for index 0 verifies")
+	}
+
+	for _, index := range []uint32{1, 2, 3, 17, 1000, 1<<32 - 1} {
+		share.SetInt64(0)
+		power.SetInt64(1)
+		for i := range bigCoeffs {
+			bi.Mul(&bigCoeffs[i], &power)
+			share.Add(&share, &bi)
+			power.Mul(&power, bi.SetUint64(uint64(index)))
+		}
+		share.Mod(&share, &l)
+		s := leBytes(&share)
+		if !edwards25519.FeldmanVerifyShare(commitments, index, &s) {
+			t.Fatalf("Valid share for index %d does not verify", index)
+		}
+		if edwards25519.FeldmanVerifyShare(commitments, index+1, &s) {
+			t.Fatalf("Share for index %d verifies for index %d", index, index+1)
+		}
+		s[0] ^= 1
+		if edwards25519.FeldmanVerifyShare(commitments, index, &s) {
+			t.Fatalf("Tampered share for index %d verifies", index)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+// ElGamal encryption of group elements over the Ristretto group.
+//
+// A private key is a Scalar x and the corresponding public key is the
+// Point X = x*B, where B is the basepoint.  A ciphertext of the Point m
+// is the pair (c1, c2) = (r*B, m + r*X) for a random Scalar r, which
+// decrypts to c2 - x*c1 = m.
+//
+// Ciphertexts are additively homomorphic: the componentwise sum of the
+// ciphertexts of m and m' is a ciphertext of m + m'.  Encrypting v*G for a
+// fixed generator G (exponential ElGamal) thus allows to add up the
+// values v, for instance to tally votes.
+package elgamal
+
+import (
+	"errors"
+	"io"
+
+	"github.com/bwesterb/go-ristretto"
+)
+
+// Encrypts m under the public key pub with randomness drawn from rng, or
+// from crypto/rand if rng is nil.  Returns the ciphertext (c1, c2).
+//
+// Returns an error if the randomness r is zero, which only happens for a
+// broken rng: then c2 would be m in the clear.
+func Encrypt(pub, m *ristretto.Point, rng io.Reader) (
+	c1, c2 *ristretto.Point, err error) {
+	var r ristretto.Scalar
+	var tmp ristretto.Point
+	if _, err = r.RandFrom(rng); err != nil {
+		return nil, nil, err
+	}
+	if r.IsNonZeroI() == 0 {
+		return nil, nil, errors.New("Randomness is zero")
+	}
+	c1 = new(ristretto.Point).ScalarMultBase(&r)
+	c2 = new(ristretto.Point).Add(m, tmp.ScalarMult(pub, &r))
+	return c1, c2, nil
+}
+
+// Returns the plaintext c2 - priv*c1 of the ciphertext (c1, c2).
+func Decrypt(priv *ristretto.Scalar, c1, c2 *ristretto.Point) *ristretto.Point {
+	var tmp ristretto.Point
+	return new(ristretto.Point).Sub(c2, tmp.ScalarMult(c1, priv))
+}
+
+// Returns the ciphertext (c1 + d1, c2 + d2), which encrypts the sum of the
+// plaintexts of (c1, c2) and (d1, d2), if both are encrypted under the
+// same public key.
+func Add(c1, c2, d1, d2 *ristretto.Point) (e1, e2 *ristretto.Point) {
+	e1 = new(ristretto.Point).Add(c1, d1)
+	e2 = new(ristretto.Point).Add(c2, d2)
+	return
+}
+
+// Returns the ciphertext (c1 + r*B, c2 + r*pub), which encrypts the same
+// plaintext as (c1, c2) under the public key pub.  For a uniformly random
+// r, the result can not be linked to (c1, c2) by anyone who does not know
+// the private key.
+func Rerandomize(c1, c2, pub *ristretto.Point, r *ristretto.Scalar) (
+	c1p, c2p *ristretto.Point) {
+	var tmp ristretto.Point
+	c1p = new(ristretto.Point).Add(c1, tmp.ScalarMultBase(r))
+	c2p = new(ristretto.Point).Add(c2, tmp.ScalarMult(pub, r))
+	return
+}
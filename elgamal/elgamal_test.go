@@ -0,0 +1,86 @@
+package elgamal_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/bwesterb/go-ristretto"
+	"github.com/bwesterb/go-ristretto/elgamal"
+)
+
+func TestRerandomize(t *testing.T) {
+	var priv, r, r2 ristretto.Scalar
+	var pub, m, c1, c2, tmp, decrypted ristretto.Point
+	for i := 0; i < 100; i++ {
+		priv.Rand()
+		pub.ScalarMultBase(&priv)
+		m.Rand()
+		r.Rand()
+		c1.ScalarMultBase(&r)
+		c2.Add(&m, tmp.ScalarMult(&pub, &r))
+
+		r2.Rand()
+		c1p, c2p := elgamal.Rerandomize(&c1, &c2, &pub, &r2)
+		if c1p.Equals(&c1) || c2p.Equals(&c2) {
+			t.Fatalf("Rerandomize did not change the ciphertext")
+		}
+		decrypted.Sub(c2p, tmp.ScalarMult(c1p, &priv))
+		if !decrypted.Equals(&m) {
+			t.Fatalf("Rerandomized ciphertext decrypts to %v != %v", decrypted, m)
+		}
+	}
+}
+
+func TestEncryptDecrypt(t *testing.T) {
+	var priv ristretto.Scalar
+	var pub, m ristretto.Point
+	for i := 0; i < 100; i++ {
+		priv.Rand()
+		pub.ScalarMultBase(&priv)
+		m.Rand()
+		c1, c2, err := elgamal.Encrypt(&pub, &m, nil)
+		if err != nil {
+			t.Fatalf("Encrypt: %v", err)
+		}
+		if c2.Equals(&m) {
+			t.Fatalf("Encrypt did not hide the plaintext")
+		}
+		if decrypted := elgamal.Decrypt(&priv, c1, c2); !decrypted.Equals(&m) {
+			t.Fatalf("Decrypt(Encrypt(%v)) = %v", m, decrypted)
+		}
+	}
+	if _, _, err := elgamal.Encrypt(&pub, &m, bytes.NewReader(nil)); err == nil {
+		t.Fatalf("Encrypt ignores a failing reader")
+	}
+	if _, _, err := elgamal.Encrypt(&pub, &m,
+		bytes.NewReader(make([]byte, 64))); err == nil {
+		t.Fatalf("Encrypt accepts zero randomness")
+	}
+}
+
+func TestAdd(t *testing.T) {
+	var priv, v ristretto.Scalar
+	var pub, G, want, vote ristretto.Point
+	priv.Rand()
+	pub.ScalarMultBase(&priv)
+	G.Rand()
+
+	// Tally ten votes of 0 or 1 in the exponent of G
+	var tally uint8
+	c1, c2, _ := elgamal.Encrypt(&pub, vote.SetZero(), nil)
+	for i := 0; i < 10; i++ {
+		b := uint8(i % 3 % 2)
+		tally += b
+		v.SetBytes(&[32]byte{b})
+		d1, d2, err := elgamal.Encrypt(&pub, vote.ScalarMult(&G, &v), nil)
+		if err != nil {
+			t.Fatalf("Encrypt: %v", err)
+		}
+		c1, c2 = elgamal.Add(c1, c2, d1, d2)
+	}
+	v.SetBytes(&[32]byte{tally})
+	want.ScalarMult(&G, &v)
+	if decrypted := elgamal.Decrypt(&priv, c1, c2); !decrypted.Equals(&want) {
+		t.Fatalf("Sum of ciphertexts decrypts to %v != [%d]G", decrypted, tally)
+	}
+}
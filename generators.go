@@ -0,0 +1,84 @@
+package ristretto
+
+import (
+	"encoding/binary"
+	"sync"
+
+	"github.com/bwesterb/go-ristretto/edwards25519"
+)
+
+// Number of NUMS points and length of the inner-product generator vectors
+// in the GeneratorSet returned by Generators().
+const GeneratorCount = 64
+
+// A set of fixed generators, as returned by Generators().  The points are
+// shared and must not be modified.
+type GeneratorSet struct {
+	// The second generator for Pedersen commitments v*B + r*H.
+	// It equals NUMSPoint(0).
+	PedersenH *Point
+
+	// NUMSPoint(i) for 0 <= i < GeneratorCount.
+	NUMS []*Point
+
+	// The generators of edwards25519.InnerProductGenerators() for
+	// vectors of length GeneratorCount, as used by Bulletproofs.
+	BulletproofG, BulletproofH []*Point
+	BulletproofU               *Point
+}
+
+// The GeneratorSet of Generators(), derived on first use.
+var (
+	generators     *GeneratorSet
+	generatorsOnce sync.Once
+)
+
+// Domain separation string for NUMSPoint().  Changing it changes every
+// derived generator.
+const numsDomain = "go-ristretto NUMS point v1"
+
+// Returns the index-th "nothing-up-my-sleeve" point.
+//
+// The point is derived by hashing the fixed domain string
+// "go-ristretto NUMS point v1" followed by the big-endian index to the
+// group using Point.DeriveDalek(), which is the hash-to-group method of the
+// ristretto255 RFC.  No-one knows the discrete logarithm of these points
+// with respect to the basepoint or each other, and they are stable across
+// versions and implementations.
+func NUMSPoint(index uint32) *Point {
+	var p Point
+	buf := make([]byte, len(numsDomain)+4)
+	copy(buf, numsDomain)
+	binary.BigEndian.PutUint32(buf[len(numsDomain):], index)
+	return p.DeriveDalek(buf)
+}
+
+// Returns the set of fixed generators.  They are derived on the first
+// call, which takes a few milliseconds, and cached for later calls.  It is
+// safe to call Generators() concurrently.
+func Generators() *GeneratorSet {
+	generatorsOnce.Do(func() {
+		generators = deriveGenerators()
+	})
+	return generators
+}
+
+// Derives the GeneratorSet returned by Generators().
+func deriveGenerators() *GeneratorSet {
+	var ret GeneratorSet
+	ret.NUMS = make([]*Point, GeneratorCount)
+	for i := range ret.NUMS {
+		ret.NUMS[i] = NUMSPoint(uint32(i))
+	}
+	ret.PedersenH = ret.NUMS[0]
+
+	G, H, u := edwards25519.InnerProductGenerators(GeneratorCount)
+	ret.BulletproofG = make([]*Point, GeneratorCount)
+	ret.BulletproofH = make([]*Point, GeneratorCount)
+	for i := 0; i < GeneratorCount; i++ {
+		ret.BulletproofG[i] = (*Point)(G[i])
+		ret.BulletproofH[i] = (*Point)(H[i])
+	}
+	ret.BulletproofU = (*Point)(u)
+	return &ret
+}
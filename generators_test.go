@@ -0,0 +1,76 @@
+package ristretto_test
+
+import (
+	"encoding/hex"
+	"sync"
+	"testing"
+
+	"github.com/bwesterb/go-ristretto"
+	"github.com/bwesterb/go-ristretto/edwards25519"
+)
+
+func TestNUMSPoint(t *testing.T) {
+	for i, expected := range []string{
+		"78d732632ed71835bad444458f4d721df0ab2d66311167e554c3c40f0cfb0b67",
+		"7052dcbdc531e9013df5d18570036983061391f7396ad0687bc23ae84795cf4d",
+		"0a2438509affad7bb7d704b82df40bb2b26bc6e6e7d380e4c98acfb6659c1b0b",
+		"50e622db19edb2443822a00995c971c2cf7dde476c8634bbfd444ed17d966f31",
+		"e26d87b28ee562dff0cce44d4c0865abdb44e505fd6068d89cc1e2a73dd62b35",
+	} {
+		got := hex.EncodeToString(ristretto.NUMSPoint(uint32(i)).Bytes())
+		if got != expected {
+			t.Fatalf("NUMSPoint(%d) = %s != %s", i, got, expected)
+		}
+	}
+}
+
+func TestGenerators(t *testing.T) {
+	var wg sync.WaitGroup
+	sets := make([]*ristretto.GeneratorSet, 16)
+	for i := range sets {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sets[i] = ristretto.Generators()
+		}(i)
+	}
+	wg.Wait()
+
+	// The generators are derived once, so every call returns the same set
+	gens := sets[0]
+	for i := range sets {
+		if sets[i] != gens {
+			t.Fatalf("Generators() returns different sets")
+		}
+	}
+	if ristretto.Generators() != gens {
+		t.Fatalf("Generators() returns different sets")
+	}
+
+	if len(gens.NUMS) != ristretto.GeneratorCount {
+		t.Fatalf("Generators() has %d NUMS points", len(gens.NUMS))
+	}
+	for i := range gens.NUMS {
+		if !gens.NUMS[i].Equals(ristretto.NUMSPoint(uint32(i))) {
+			t.Fatalf("Generators().NUMS[%d] != NUMSPoint(%[1]d)", i)
+		}
+	}
+	if !gens.PedersenH.Equals(ristretto.NUMSPoint(0)) {
+		t.Fatalf("Generators().PedersenH != NUMSPoint(0)")
+	}
+
+	G, H, u := edwards25519.InnerProductGenerators(ristretto.GeneratorCount)
+	if len(gens.BulletproofG) != len(G) || len(gens.BulletproofH) != len(H) {
+		t.Fatalf("Generators() has %d and %d inner-product generators",
+			len(gens.BulletproofG), len(gens.BulletproofH))
+	}
+	for i := range G {
+		if !gens.BulletproofG[i].Equals((*ristretto.Point)(G[i])) ||
+			!gens.BulletproofH[i].Equals((*ristretto.Point)(H[i])) {
+			t.Fatalf("Generators() has wrong inner-product generators at %d", i)
+		}
+	}
+	if !gens.BulletproofU.Equals((*ristretto.Point)(u)) {
+		t.Fatalf("Generators().BulletproofU is wrong")
+	}
+}
@@ -0,0 +1,97 @@
+package ristretto
+
+import (
+	"crypto/sha512"
+	"encoding/binary"
+)
+
+// Domain separation string for ChallengeFromPointScalar().
+const pointScalarChallengeDomain = "go-ristretto point-scalar challenge v1"
+
+// Returns the scalar derived with Scalar.Derive() from the encoding of p,
+// the encoding of s and extra, prefixed by a fixed domain separation string.
+//
+// This is a shorthand for a Fiat-Shamir challenge over a single point and
+// scalar, for instance to derive a nested challenge from a previous
+// commitment and response.  As p and s have a fixed-length encoding,
+// extra does not need to be length-prefixed.
+func ChallengeFromPointScalar(p *Point, s *Scalar, extra []byte) Scalar {
+	var c Scalar
+	var buf [32]byte
+	transcript := make([]byte, 0, len(pointScalarChallengeDomain)+64+len(extra))
+	transcript = append(transcript, pointScalarChallengeDomain...)
+	p.BytesInto(&buf)
+	transcript = append(transcript, buf[:]...)
+	s.BytesInto(&buf)
+	transcript = append(transcript, buf[:]...)
+	transcript = append(transcript, extra...)
+	c.Derive(transcript)
+	return c
+}
+
+// Domain separation string for HashPointToScalar().
+const pointToScalarDomain = "go-ristretto point to scalar v1"
+
+// Returns the scalar derived with Scalar.Derive() from the encoding of p,
+// prefixed by a fixed domain separation string.
+//
+// As it only depends on the encoding, all parties obtain the same scalar
+// for the same group element, which is what the construction of key
+// images in linkable ring signatures requires.  Unlike hashing to a point,
+// the result is a scalar.
+func HashPointToScalar(p *Point) *Scalar {
+	var buf [32]byte
+	transcript := make([]byte, 0, len(pointToScalarDomain)+32)
+	transcript = append(transcript, pointToScalarDomain...)
+	p.BytesInto(&buf)
+	transcript = append(transcript, buf[:]...)
+	return new(Scalar).Derive(transcript)
+}
+
+// Domain separation string for HashToTwoPoints().
+const twoPointsDomain = "go-ristretto two points v1"
+
+// Returns two points derived from msg.
+//
+// Expands msg to 128 bytes with SHA512 in counter mode, hashing the
+// domain separation string, a counter byte and msg, and maps each 64-byte
+// block to the group as Point.DeriveDalek() does.  Modelling SHA512 as a
+// random oracle, the blocks are independent and so are the points: no-one
+// knows the discrete logarithm of one with respect to the other.
+func HashToTwoPoints(msg []byte) (*Point, *Point) {
+	var p1, p2 Point
+	var block [64]byte
+	h := sha512.New()
+	for i, p := range []*Point{&p1, &p2} {
+		h.Reset()
+		h.Write([]byte(twoPointsDomain))
+		h.Write([]byte{byte(i)})
+		h.Write(msg)
+		h.Sum(block[:0])
+		p.setUniformBytes(&block)
+	}
+	return &p1, &p2
+}
+
+// Domain separation string for OPRFFinalize().
+const oprfFinalizeDomain = "go-ristretto OPRF finalize v1"
+
+// Returns the output of an oblivious PRF on input, where evaluated is the
+// unblinded evaluation of the server on the point input was hashed to.
+//
+// The output is SHA512 of a domain separation string, the length of input
+// as a big-endian 64-bit integer, input and the encoding of evaluated.
+// Including input binds the output to it, not just to its point.
+func OPRFFinalize(input []byte, evaluated *Point) [64]byte {
+	var buf [32]byte
+	var ret [64]byte
+	h := sha512.New()
+	h.Write([]byte(oprfFinalizeDomain))
+	binary.BigEndian.PutUint64(buf[:8], uint64(len(input)))
+	h.Write(buf[:8])
+	h.Write(input)
+	evaluated.BytesInto(&buf)
+	h.Write(buf[:])
+	h.Sum(ret[:0])
+	return ret
+}
@@ -0,0 +1,95 @@
+package ristretto_test
+
+import (
+	"testing"
+
+	"github.com/bwesterb/go-ristretto"
+)
+
+func TestChallengeFromPointScalar(t *testing.T) {
+	var p, p2 ristretto.Point
+	var s, s2 ristretto.Scalar
+	extra := []byte("extra")
+	for i := 0; i < 100; i++ {
+		p.Rand()
+		s.Rand()
+		c := ristretto.ChallengeFromPointScalar(&p, &s, extra)
+		c2 := ristretto.ChallengeFromPointScalar(&p, &s, extra)
+		if !c.Equals(&c2) {
+			t.Fatalf("ChallengeFromPointScalar is not deterministic")
+		}
+		p2.Rand()
+		c2 = ristretto.ChallengeFromPointScalar(&p2, &s, extra)
+		if c.Equals(&c2) {
+			t.Fatalf("ChallengeFromPointScalar ignores the point")
+		}
+		s2.Rand()
+		c2 = ristretto.ChallengeFromPointScalar(&p, &s2, extra)
+		if c.Equals(&c2) {
+			t.Fatalf("ChallengeFromPointScalar ignores the scalar")
+		}
+		c2 = ristretto.ChallengeFromPointScalar(&p, &s, []byte("extrb"))
+		if c.Equals(&c2) {
+			t.Fatalf("ChallengeFromPointScalar ignores the extra data")
+		}
+		c2 = ristretto.ChallengeFromPointScalar(&p, &s, nil)
+		if c.Equals(&c2) {
+			t.Fatalf("ChallengeFromPointScalar ignores the extra data")
+		}
+	}
+}
+
+func TestHashPointToScalar(t *testing.T) {
+	var p, q ristretto.Point
+	for i := 0; i < 100; i++ {
+		p.Rand()
+		h := ristretto.HashPointToScalar(&p)
+		if !h.Equals(ristretto.HashPointToScalar(&p)) {
+			t.Fatalf("HashPointToScalar is not deterministic")
+		}
+
+		// Depends on the group element only
+		q.SetBytes(&[32]byte{})
+		q.Add(&q, &p)
+		if !h.Equals(ristretto.HashPointToScalar(&q)) {
+			t.Fatalf("HashPointToScalar differs for equal points")
+		}
+		q.Double(&p)
+		if h.Equals(ristretto.HashPointToScalar(&q)) {
+			t.Fatalf("HashPointToScalar(%v) = HashPointToScalar(%v)", &p, &q)
+		}
+	}
+}
+
+func TestHashToTwoPoints(t *testing.T) {
+	p1, p2 := ristretto.HashToTwoPoints([]byte("seed"))
+	q1, q2 := ristretto.HashToTwoPoints([]byte("seed"))
+	if !p1.Equals(q1) || !p2.Equals(q2) {
+		t.Fatalf("HashToTwoPoints is not deterministic")
+	}
+	if p1.Equals(p2) {
+		t.Fatalf("HashToTwoPoints returns the same point twice")
+	}
+	q1, q2 = ristretto.HashToTwoPoints([]byte("seee"))
+	if p1.Equals(q1) || p2.Equals(q2) {
+		t.Fatalf("HashToTwoPoints ignores the message")
+	}
+}
+
+func TestOPRFFinalize(t *testing.T) {
+	var p, p2 ristretto.Point
+	for i := 0; i < 100; i++ {
+		p.Rand()
+		out := ristretto.OPRFFinalize([]byte("input"), &p)
+		if out != ristretto.OPRFFinalize([]byte("input"), &p) {
+			t.Fatalf("OPRFFinalize is not deterministic")
+		}
+		if out == ristretto.OPRFFinalize([]byte("inpuT"), &p) {
+			t.Fatalf("OPRFFinalize ignores the input")
+		}
+		p2.Rand()
+		if out == ristretto.OPRFFinalize([]byte("input"), &p2) {
+			t.Fatalf("OPRFFinalize ignores the point")
+		}
+	}
+}
@@ -0,0 +1,92 @@
+// An oblivious pseudorandom function over the Ristretto group, following
+// the 2HashDH construction of Jarecki, Kiayias and Krawczyk.
+//
+// The server holds a Scalar k and the PRF is
+//
+//	F(k, x) = H2(x || k*H1(x)),
+//
+// where H1 hashes to the group with Point.DeriveDalek() and H2 is
+// SHA-512/256.  To evaluate F on x without revealing it, the client sends
+// the blinded point r*H1(x) for a random Scalar r.  The server multiplies
+// it by k and the client removes the blind by multiplying with 1/r.
+//
+// A client may learn F(k, x) this way without the server learning x, and
+// without the client learning k.  The outputs are specific to this package.
+package oprf
+
+import (
+	"crypto/sha512"
+	"errors"
+	"io"
+
+	"github.com/bwesterb/go-ristretto"
+)
+
+// Domain separation strings.
+const (
+	hashToPointDomain = "go-ristretto OPRF hash to point v1"
+	outputDomain      = "go-ristretto OPRF output v1"
+)
+
+// Blinds input with a random Scalar r drawn from rng, or from crypto/rand
+// if rng is nil.  Returns the blinded point r*H1(input), which is sent to
+// the server, and r, which the client keeps for Finalize().
+//
+// Returns an error if r is zero, which only happens for a broken rng: the
+// blinded point would be zero, and so would the PRF output for every key.
+func Blind(input []byte, rng io.Reader) (
+	blinded *ristretto.Point, r *ristretto.Scalar, err error) {
+	var H ristretto.Point
+	r = new(ristretto.Scalar)
+	if _, err = r.RandFrom(rng); err != nil {
+		return nil, nil, err
+	}
+	if r.IsNonZeroI() == 0 {
+		return nil, nil, errors.New("Blind is zero")
+	}
+	blinded = new(ristretto.Point).ScalarMult(hashToPoint(&H, input), r)
+	return blinded, r, nil
+}
+
+// Returns key*blinded, the server's response to the blinded point sent
+// by a client.
+func Evaluate(key *ristretto.Scalar, blinded *ristretto.Point) *ristretto.Point {
+	return new(ristretto.Point).ScalarMult(blinded, key)
+}
+
+// Returns the PRF output on input from the server's response evaluated
+// to the point blinded with r by Blind().
+func Finalize(input []byte, r *ristretto.Scalar,
+	evaluated *ristretto.Point) [32]byte {
+	var rInv ristretto.Scalar
+	var unblinded ristretto.Point
+	unblinded.ScalarMult(evaluated, rInv.Inverse(r))
+	return output(input, &unblinded)
+}
+
+// Returns the PRF output on input with key key, as computed by a client
+// with Blind(), Evaluate() and Finalize().
+func PRF(key *ristretto.Scalar, input []byte) [32]byte {
+	var H ristretto.Point
+	H.ScalarMult(hashToPoint(&H, input), key)
+	return output(input, &H)
+}
+
+// Sets H to the hash H1(input) to the group.  Returns H.
+func hashToPoint(H *ristretto.Point, input []byte) *ristretto.Point {
+	transcript := make([]byte, 0, len(hashToPointDomain)+len(input))
+	transcript = append(transcript, hashToPointDomain...)
+	transcript = append(transcript, input...)
+	return H.DeriveDalek(transcript)
+}
+
+// Returns H2(input || P).
+func output(input []byte, P *ristretto.Point) [32]byte {
+	var buf [32]byte
+	P.BytesInto(&buf)
+	transcript := make([]byte, 0, len(outputDomain)+len(input)+32)
+	transcript = append(transcript, outputDomain...)
+	transcript = append(transcript, input...)
+	transcript = append(transcript, buf[:]...)
+	return sha512.Sum512_256(transcript)
+}
@@ -0,0 +1,69 @@
+package oprf_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/bwesterb/go-ristretto"
+	"github.com/bwesterb/go-ristretto/oprf"
+)
+
+func TestOPRF(t *testing.T) {
+	var key, otherKey ristretto.Scalar
+	input := []byte("input")
+	for i := 0; i < 50; i++ {
+		key.Rand()
+		want := oprf.PRF(&key, input)
+
+		blinded, r, err := oprf.Blind(input, nil)
+		if err != nil {
+			t.Fatalf("Blind: %v", err)
+		}
+		blinded2, r2, err := oprf.Blind(input, nil)
+		if err != nil {
+			t.Fatalf("Blind: %v", err)
+		}
+		if blinded.Equals(blinded2) {
+			t.Fatalf("Blind is deterministic")
+		}
+
+		got := oprf.Finalize(input, r, oprf.Evaluate(&key, blinded))
+		got2 := oprf.Finalize(input, r2, oprf.Evaluate(&key, blinded2))
+		if got != want || got2 != want {
+			t.Fatalf("Finalize: %x, %x != %x", got, got2, want)
+		}
+
+		otherKey.Rand()
+		if oprf.Finalize(input, r, oprf.Evaluate(&otherKey, blinded)) == want {
+			t.Fatalf("Finalize agrees for other key")
+		}
+		if oprf.Finalize([]byte("other input"), r,
+			oprf.Evaluate(&key, blinded)) == want {
+			t.Fatalf("Finalize agrees for other input")
+		}
+		if oprf.PRF(&key, []byte("other input")) == want {
+			t.Fatalf("PRF agrees for other input")
+		}
+	}
+}
+
+func TestBlindRng(t *testing.T) {
+	input := []byte("input")
+	if _, _, err := oprf.Blind(input, bytes.NewReader(nil)); err == nil {
+		t.Fatalf("Blind does not return error of rng")
+	}
+	if _, _, err := oprf.Blind(input,
+		bytes.NewReader(make([]byte, 64))); err == nil {
+		t.Fatalf("Blind accepts a zero blind")
+	}
+
+	seed := bytes.Repeat([]byte{1}, 64)
+	blinded, r, err := oprf.Blind(input, bytes.NewReader(seed))
+	if err != nil {
+		t.Fatalf("Blind: %v", err)
+	}
+	blinded2, r2, _ := oprf.Blind(input, bytes.NewReader(seed))
+	if !blinded.Equals(blinded2) || !r.Equals(r2) {
+		t.Fatalf("Blind does not use rng")
+	}
+}
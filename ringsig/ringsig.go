@@ -0,0 +1,160 @@
+// Linkable ring signatures over the Ristretto group.
+//
+// A ring signature on a message proves that it was created by the owner
+// of one of the public keys in the ring, without revealing which.  This
+// package implements the LSAG scheme of Liu, Wei and Wong: every signature
+// contains the key image I = a*Hp(A) of the signer with private key a and
+// public key A = a*B, where Hp hashes to the group.  The key image does
+// not depend on the ring or message, so two signatures by the same signer
+// can be linked by comparing their key images.
+package ringsig
+
+import (
+	"errors"
+
+	"github.com/bwesterb/go-ristretto"
+)
+
+// Domain separation string for the hash to the group of public keys.
+const keyImageDomain = "go-ristretto ringsig key image v1"
+
+// Domain separation string for the challenges.
+const challengeDomain = "go-ristretto ringsig v1"
+
+// Signs msg with the private key priv on behalf of ring, which must contain
+// the public key of priv.  Returns the signature, which is 32*(len(ring)+2)
+// bytes long.
+func RingSign(priv *ristretto.Scalar, ring []*ristretto.Point, msg []byte) (
+	[]byte, error) {
+	var pub, Hp, I, L, R, tmp ristretto.Point
+	var alpha ristretto.Scalar
+	n := len(ring)
+	pub.ScalarMultBase(priv)
+	signer := -1
+	for i := 0; i < n; i++ {
+		if ring[i].Equals(&pub) {
+			signer = i
+			break
+		}
+	}
+	if signer == -1 {
+		return nil, errors.New("Public key of the signer is not in the ring")
+	}
+
+	prefix := transcriptPrefix(ring, keyImage(&I, priv, &pub))
+	c := make([]ristretto.Scalar, n)
+	s := make([]ristretto.Scalar, n)
+
+	// Start the ring at the signer with L = alpha*B and R = alpha*Hp(A).
+	alpha.Rand()
+	L.ScalarMultBase(&alpha)
+	R.ScalarMult(hashToPoint(&Hp, &pub), &alpha)
+	challenge(&c[(signer+1)%n], prefix, msg, &L, &R)
+
+	// Go around the ring with random responses for the other members.
+	for j := 1; j < n; j++ {
+		i := (signer + j) % n
+		s[i].Rand()
+		L.PublicScalarMultBase(&s[i])
+		L.Add(&L, tmp.PublicScalarMult(ring[i], &c[i]))
+		R.PublicScalarMult(hashToPoint(&Hp, ring[i]), &s[i])
+		R.Add(&R, tmp.PublicScalarMult(&I, &c[i]))
+		challenge(&c[(i+1)%n], prefix, msg, &L, &R)
+	}
+
+	// Close the ring: s = alpha - c*a.
+	s[signer].MulSub(&c[signer], priv, &alpha)
+	s[signer].Neg(&s[signer])
+
+	var buf [32]byte
+	sig := make([]byte, 0, 32*(n+2))
+	c[0].BytesInto(&buf)
+	sig = append(sig, buf[:]...)
+	for i := 0; i < n; i++ {
+		s[i].BytesInto(&buf)
+		sig = append(sig, buf[:]...)
+	}
+	I.BytesInto(&buf)
+	return append(sig, buf[:]...), nil
+}
+
+// Returns whether sig is a valid signature on msg by a member of ring
+// and, if so, the key image of the signer.
+func RingVerify(ring []*ristretto.Point, msg, sig []byte) (
+	ok bool, keyImage *ristretto.Point) {
+	var c, c0, s ristretto.Scalar
+	var I, Hp, L, R, tmp ristretto.Point
+	var buf [32]byte
+	n := len(ring)
+	if n == 0 || len(sig) != 32*(n+2) {
+		return false, nil
+	}
+	copy(buf[:], sig[32*(n+1):])
+	if !I.SetBytes(&buf) {
+		return false, nil
+	}
+	copy(buf[:], sig[:32])
+	if c0.SetCanonicalBytes(&buf) != nil {
+		return false, nil
+	}
+
+	prefix := transcriptPrefix(ring, &I)
+	c.Set(&c0)
+	for i := 0; i < n; i++ {
+		copy(buf[:], sig[32*(i+1):])
+		if s.SetCanonicalBytes(&buf) != nil {
+			return false, nil
+		}
+		L.PublicScalarMultBase(&s)
+		L.Add(&L, tmp.PublicScalarMult(ring[i], &c))
+		R.PublicScalarMult(hashToPoint(&Hp, ring[i]), &s)
+		R.Add(&R, tmp.PublicScalarMult(&I, &c))
+		challenge(&c, prefix, msg, &L, &R)
+	}
+	if !c.Equals(&c0) {
+		return false, nil
+	}
+	return true, &I
+}
+
+// Sets I to the key image priv*Hp(pub).  Returns I.
+func keyImage(I *ristretto.Point, priv *ristretto.Scalar,
+	pub *ristretto.Point) *ristretto.Point {
+	var Hp ristretto.Point
+	return I.ScalarMult(hashToPoint(&Hp, pub), priv)
+}
+
+// Sets p to the hash Hp(pub) of the public key pub to the group.  Returns p.
+func hashToPoint(p, pub *ristretto.Point) *ristretto.Point {
+	var buf [32]byte
+	pub.BytesInto(&buf)
+	return p.DeriveDalek(append([]byte(keyImageDomain), buf[:]...))
+}
+
+// Returns the part of the challenge transcript shared by all challenges:
+// the domain separation string, the ring and the key image I.
+func transcriptPrefix(ring []*ristretto.Point, I *ristretto.Point) []byte {
+	var buf [32]byte
+	ret := make([]byte, 0, len(challengeDomain)+32*(len(ring)+1))
+	ret = append(ret, challengeDomain...)
+	for _, pub := range ring {
+		pub.BytesInto(&buf)
+		ret = append(ret, buf[:]...)
+	}
+	I.BytesInto(&buf)
+	return append(ret, buf[:]...)
+}
+
+// Sets c to the challenge H(prefix || L || R || msg).  Returns c.
+func challenge(c *ristretto.Scalar, prefix, msg []byte,
+	L, R *ristretto.Point) *ristretto.Scalar {
+	var buf [32]byte
+	transcript := make([]byte, 0, len(prefix)+64+len(msg))
+	transcript = append(transcript, prefix...)
+	L.BytesInto(&buf)
+	transcript = append(transcript, buf[:]...)
+	R.BytesInto(&buf)
+	transcript = append(transcript, buf[:]...)
+	transcript = append(transcript, msg...)
+	return c.Derive(transcript)
+}
@@ -0,0 +1,87 @@
+package ringsig_test
+
+import (
+	"testing"
+
+	"github.com/bwesterb/go-ristretto"
+	"github.com/bwesterb/go-ristretto/ringsig"
+)
+
+func newRing(n int) ([]ristretto.Scalar, []*ristretto.Point) {
+	privs := make([]ristretto.Scalar, n)
+	ring := make([]*ristretto.Point, n)
+	for i := 0; i < n; i++ {
+		privs[i].Rand()
+		ring[i] = new(ristretto.Point).ScalarMultBase(&privs[i])
+	}
+	return privs, ring
+}
+
+func TestRingSignVerify(t *testing.T) {
+	msg := []byte("test message")
+	privs, ring := newRing(5)
+	images := make([]*ristretto.Point, len(ring))
+	for i := 0; i < len(ring); i++ {
+		sig, err := ringsig.RingSign(&privs[i], ring, msg)
+		if err != nil {
+			t.Fatalf("RingSign: %v", err)
+		}
+		if len(sig) != 32*(len(ring)+2) {
+			t.Fatalf("len(sig) = %d", len(sig))
+		}
+		ok, keyImage := ringsig.RingVerify(ring, msg, sig)
+		if !ok {
+			t.Fatalf("Signature of member %d does not verify", i)
+		}
+		images[i] = keyImage
+		if ok, _ := ringsig.RingVerify(ring, []byte("other message"), sig); ok {
+			t.Fatalf("Signature verifies for another message")
+		}
+		if ok, _ := ringsig.RingVerify(ring[:4], msg, sig[32:]); ok {
+			t.Fatalf("Signature verifies for a smaller ring")
+		}
+		sig[40] ^= 1
+		if ok, _ := ringsig.RingVerify(ring, msg, sig); ok {
+			t.Fatalf("Tampered signature verifies")
+		}
+	}
+	for i := 0; i < len(images); i++ {
+		for j := 0; j < i; j++ {
+			if images[i].Equals(images[j]) {
+				t.Fatalf("Members %d and %d have the same key image", i, j)
+			}
+		}
+	}
+}
+
+func TestRingSignLinkable(t *testing.T) {
+	privs, ring := newRing(5)
+	_, other := newRing(4)
+	other = append(other, ring[2])
+
+	sig1, err := ringsig.RingSign(&privs[2], ring, []byte("first"))
+	if err != nil {
+		t.Fatalf("RingSign: %v", err)
+	}
+	sig2, err := ringsig.RingSign(&privs[2], other, []byte("second"))
+	if err != nil {
+		t.Fatalf("RingSign: %v", err)
+	}
+	ok1, image1 := ringsig.RingVerify(ring, []byte("first"), sig1)
+	ok2, image2 := ringsig.RingVerify(other, []byte("second"), sig2)
+	if !ok1 || !ok2 {
+		t.Fatalf("Signatures do not verify")
+	}
+	if !image1.Equals(image2) {
+		t.Fatalf("Signatures by the same signer are not linked")
+	}
+}
+
+func TestRingSignNotMember(t *testing.T) {
+	var priv ristretto.Scalar
+	_, ring := newRing(5)
+	priv.Rand()
+	if _, err := ringsig.RingSign(&priv, ring, []byte("msg")); err == nil {
+		t.Fatalf("RingSign accepts a signer outside the ring")
+	}
+}
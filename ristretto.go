@@ -177,6 +177,23 @@ func (p *Point) PublicScalarMultBase(s *Scalar) *Point {
 	return p
 }
 
+// Sets p to a * B + b * q, where B is the edwards25519 basepoint, assuming
+// a and b are *not* secret.  Returns p.
+//
+// This is faster than two separate scalar multiplications, as needed to
+// verify signatures.
+//
+// Warning: this method uses a non-constant time inmplementation and thus leaks
+// information about a and b.  Use this function only if they are public
+// knowledge.
+func (p *Point) PublicDoubleScalarMultBase(a, b *Scalar, q *Point) *Point {
+	var aBuf, bBuf [32]byte
+	a.BytesInto(&aBuf)
+	b.BytesInto(&bBuf)
+	p.e().DoubleScalarMultBaseVartime(&aBuf, &bBuf, q.e())
+	return p
+}
+
 // Sets p to s * B, where B is the edwards25519 basepoint. Returns p.
 func (p *Point) ScalarMultBase(s *Scalar) *Point {
 	var buf [32]byte
@@ -308,6 +325,15 @@ func (p *Point) Equals(q *Point) bool {
 	return p.EqualsI(q) == 1
 }
 
+// Sets dst to b if bit == 1 and to a if bit == 0 in constant time.
+// Assumes bit is 0 or 1.  dst may alias a or b.
+func SelectEncoding(dst, a, b *[32]byte, bit int32) {
+	mask := byte(-bit)
+	for i := 0; i < 32; i++ {
+		dst[i] = a[i] ^ (mask & (a[i] ^ b[i]))
+	}
+}
+
 // Sets p to the point derived from the buffer using SHA512 and Elligator2
 // in the fashion of curve25519-dalek.
 //
@@ -315,13 +341,14 @@ func (p *Point) Equals(q *Point) bool {
 // but which might not be as secure as this method.
 func (p *Point) DeriveDalek(data []byte) *Point {
 	hash := sha512.Sum512(data)
-	var p2 Point
-	var buf [32]byte
-	copy(buf[:], hash[:32])
-	p.SetElligator(&buf)
-	copy(buf[:], hash[32:])
-	p2.SetElligator(&buf)
-	p.Add(p, &p2)
+	return p.setUniformBytes(&hash)
+}
+
+// Sets p to the sum of the Elligator images of both halves of buf,
+// which is the map from 64 uniform bytes to the group of the ristretto255
+// RFC.  Returns p.
+func (p *Point) setUniformBytes(buf *[64]byte) *Point {
+	p.e().SetRistrettoHash(buf)
 	return p
 }
 
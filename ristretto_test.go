@@ -150,6 +150,21 @@ func TestBasePointMultiples(t *testing.T) {
 	}
 }
 
+func TestPublicDoubleScalarMultBase(t *testing.T) {
+	var a, b ristretto.Scalar
+	var p, q, want, tmp ristretto.Point
+	for i := 0; i < 100; i++ {
+		a.Rand()
+		b.Rand()
+		q.Rand()
+		want.ScalarMultBase(&a)
+		want.Add(&want, tmp.ScalarMult(&q, &b))
+		if !p.PublicDoubleScalarMultBase(&a, &b, &q).Equals(&want) {
+			t.Fatalf("[%v]B + [%v]%v = %v != %v", a, b, q, p, want)
+		}
+	}
+}
+
 func testLizardVector(t *testing.T, in, out string) {
 	var p ristretto.Point
 	var inBuf [16]byte
@@ -190,6 +205,27 @@ func TestLizardInjective(t *testing.T) {
 	}
 }
 
+func TestSelectEncoding(t *testing.T) {
+	var a, b, dst [32]byte
+	for i := 0; i < 100; i++ {
+		rnd.Read(a[:])
+		rnd.Read(b[:])
+		ristretto.SelectEncoding(&dst, &a, &b, 0)
+		if dst != a {
+			t.Fatalf("SelectEncoding(a, b, 0) != a")
+		}
+		ristretto.SelectEncoding(&dst, &a, &b, 1)
+		if dst != b {
+			t.Fatalf("SelectEncoding(a, b, 1) != b")
+		}
+		dst = a
+		ristretto.SelectEncoding(&dst, &dst, &b, 1)
+		if dst != b {
+			t.Fatalf("SelectEncoding(dst, b, 1) != b")
+		}
+	}
+}
+
 func BenchmarkLizardDecode(b *testing.B) {
 	buf := [16]byte{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
 	var p ristretto.Point
@@ -4,7 +4,10 @@ import (
 	"crypto/rand"
 	"crypto/sha512"
 	"encoding/base64"
+	"encoding/binary"
+	"errors"
 	"fmt"
+	"io"
 
 	// Required for FieldElement.[Set]BigInt().  Obviously not used for actual
 	// implementation, as operations on big.Ints are  not constant-time.
@@ -27,6 +30,10 @@ var (
 		0x5cf5d3ed, 0x5812631a, 0xa2f79cd6, 0x14def9de,
 		0x00000000, 0x00000000, 0x00000000, 0x10000000,
 	}
+	scHalf = Scalar{ // (l+1)/2, the inverse of 2
+		0x2e7ae9f7, 0x2c09318d, 0x517bce6b, 0x0a6f7cef,
+		0x00000000, 0x00000000, 0x00000000, 0x08000000,
+	}
 )
 
 // Encode s little endian into buf. Returns s.
@@ -87,6 +94,92 @@ func (s *Scalar) SetBytes(x *[32]byte) *Scalar {
 	return s.Sub(s, &scL)
 }
 
+// Sets s to x, where x is interpreted little endian.  Returns an error
+// and leaves s unchanged if x is not the canonical encoding of a scalar,
+// that is, if x >= l.  Bytes() and BytesInto() always return the canonical
+// encoding.
+func (s *Scalar) SetCanonicalBytes(x *[32]byte) error {
+	var t Scalar
+	var buf [32]byte
+	t.SetBytes(x).BytesInto(&buf)
+	if buf != *x {
+		return errors.New("Buffer is not a canonically encoded ristretto.Scalar")
+	}
+	s.Set(&t)
+	return nil
+}
+
+// Returns the little-endian encoded scalar s as four 64-bit limbs,
+// least significant first.
+func ScalarToLimbs(s *[32]byte) [4]uint64 {
+	var limbs [4]uint64
+	for i := 0; i < 4; i++ {
+		limbs[i] = binary.LittleEndian.Uint64(s[8*i:])
+	}
+	return limbs
+}
+
+// Returns the little-endian encoding of the scalar given by four 64-bit
+// limbs, least significant first.  Does not reduce.
+func ScalarFromLimbs(limbs *[4]uint64) [32]byte {
+	var s [32]byte
+	for i := 0; i < 4; i++ {
+		binary.LittleEndian.PutUint64(s[8*i:], limbs[i])
+	}
+	return s
+}
+
+// Returns the little-endian encoding of the inner product sum_i a[i]*b[i]
+// mod l of the little-endian encoded scalars a and b, which are read as by
+// SetBytes().  Returns an error if a and b have different lengths.
+func ScalarInnerProduct(a, b []*[32]byte) (*[32]byte, error) {
+	var sum, ai, bi Scalar
+	var ret [32]byte
+	if len(a) != len(b) {
+		return nil, errors.New("Vectors of the inner product differ in length")
+	}
+	sum.SetZero()
+	for i := 0; i < len(a); i++ {
+		sum.MulAdd(ai.SetBytes(a[i]), bi.SetBytes(b[i]), &sum)
+	}
+	sum.BytesInto(&ret)
+	return &ret, nil
+}
+
+// Returns the little-endian encodings of the powers 1, x, x^2, ..., x^(n-1)
+// mod l of the little-endian encoded scalar x, which is read as by
+// SetBytes().
+func ScalarPowers(x *[32]byte, n int) [][32]byte {
+	var xs, pow Scalar
+	ret := make([][32]byte, n)
+	xs.SetBytes(x)
+	pow.SetOne()
+	for i := 0; i < n; i++ {
+		pow.BytesInto(&ret[i])
+		pow.Mul(&pow, &xs)
+	}
+	return ret
+}
+
+// Returns the n scalars obtained by reducing the consecutive 64-byte
+// blocks of in mod l, as by SetReduced().  This is useful to derive many
+// scalars from the output of an extendable-output hash function.  Returns
+// an error if in is not exactly 64*n bytes long.
+func ScalarsFromUniform(in []byte, n int) ([][32]byte, error) {
+	var s Scalar
+	var block [64]byte
+	if n < 0 || len(in) != 64*n {
+		return nil, fmt.Errorf(
+			"Input should be 64*n bytes; not %d", len(in))
+	}
+	ret := make([][32]byte, n)
+	for i := 0; i < n; i++ {
+		copy(block[:], in[64*i:])
+		s.SetReduced(&block).BytesInto(&ret[i])
+	}
+	return ret, nil
+}
+
 // Sets s to -a.  Returns s.
 func (s *Scalar) Neg(a *Scalar) *Scalar {
 	return s.Sub(&scZero, a)
@@ -113,6 +206,16 @@ func (s *Scalar) Add(a, b *Scalar) *Scalar {
 	return s.Sub(s, &scL)
 }
 
+// Sets s to 2a.  Returns s.
+func (s *Scalar) Double(a *Scalar) *Scalar {
+	return s.Add(a, a)
+}
+
+// Sets s to a/2, that is: the unique h with 2h = a.  Returns s.
+func (s *Scalar) Halve(a *Scalar) *Scalar {
+	return s.Mul(a, &scHalf)
+}
+
 // Sets s to a - b.  Returns s.
 func (s *Scalar) Sub(a, b *Scalar) *Scalar {
 	borrow := uint64(a[0]) - uint64(b[0])
@@ -369,7 +472,25 @@ func (s *Scalar) Derive(buf []byte) *Scalar {
 	return s.SetReduced(&sBuf)
 }
 
+// Sets s to in mod l, where in is interpreted little endian and is between
+// 32 and 64 bytes long.  Returns s.  Returns an error and leaves s unchanged
+// if in has a different length.  See also SetReduced().
+func (s *Scalar) SetWideBytes(in []byte) (*Scalar, error) {
+	var buf [64]byte
+	if len(in) < 32 || len(in) > 64 {
+		return nil, fmt.Errorf(
+			"Input should be between 32 and 64 bytes; not %d", len(in))
+	}
+	copy(buf[:], in)
+	return s.SetReduced(&buf), nil
+}
+
 // Sets s to t mod l, where t is interpreted little endian.  Returns s.
+//
+// This is the operation that the ristretto255 RFC calls from_uniform_bytes.
+// For uniformly random t, such as a SHA-512 hash, the result is uniform
+// modulo l up to a negligible bias of about 2^-259.  It runs in constant
+// time.
 func (s *Scalar) SetReduced(t *[64]byte) *Scalar {
 	t0 := 0x1FFFFF & load3(t[:])
 	t1 := 0x1FFFFF & (load4(t[2:]) >> 5)
@@ -666,13 +787,27 @@ func (s *Scalar) SetReduced(t *[64]byte) *Scalar {
 	return s
 }
 
-// Sets s to a random scalar.  Returns s.
+// Sets s to a random scalar.  Returns s.  See also RandFrom().
 func (s *Scalar) Rand() *Scalar {
 	var buf [64]byte
 	rand.Read(buf[:])
 	return s.SetReduced(&buf)
 }
 
+// Sets s to a uniformly random scalar derived from 64 bytes read from rng
+// with SetReduced().  If rng is nil, crypto/rand is used.  Returns s.
+// Returns an error and leaves s unchanged if reading from rng fails.
+func (s *Scalar) RandFrom(rng io.Reader) (*Scalar, error) {
+	var buf [64]byte
+	if rng == nil {
+		rng = rand.Reader
+	}
+	if _, err := io.ReadFull(rng, buf[:]); err != nil {
+		return nil, err
+	}
+	return s.SetReduced(&buf), nil
+}
+
 // Sets s to a*a.  Returns s.
 func (s *Scalar) Square(a *Scalar) *Scalar {
 	a0 := int64(a[0] & 0x1fffff)
@@ -769,6 +904,9 @@ func (s *Scalar) Mul(a, b *Scalar) *Scalar {
 }
 
 // Sets s to 1/t.  Returns s.
+//
+// This computes t^(l-2) with a fixed addition chain, so it is constant-time.
+// If t is zero, s is set to zero.
 func (s *Scalar) Inverse(t *Scalar) *Scalar {
 	var t0, t1, t2, t3, t4, t5 Scalar
 
@@ -975,6 +1113,32 @@ func (s *Scalar) Inverse(t *Scalar) *Scalar {
 	return s
 }
 
+// Number of bits of a reduced scalar: l < 2^253.
+const ScalarBits = 253
+
+// Writes the little endian bits of s into out, that is: out[i] is the
+// i-th bit of s, for 0 <= i < ScalarBits.  Panics if out is shorter than
+// ScalarBits.  Entries of out beyond ScalarBits are left untouched.
+// Runs in constant time.
+func (s *Scalar) Bits(out []int8) {
+	if len(out) < ScalarBits {
+		panic("ristretto: Bits: out is shorter than ScalarBits")
+	}
+	for i := 0; i < ScalarBits; i++ {
+		out[i] = int8((s[i>>5] >> uint(i&31)) & 1)
+	}
+}
+
+// Returns the i-th bit of s, where the 0th bit is the least significant.
+// Panics if i is not in the range [0, ScalarBits).  Runs in time
+// independent of s.
+func (s *Scalar) BitAt(i int) uint {
+	if i < 0 || i >= ScalarBits {
+		panic("ristretto: BitAt: index out of range")
+	}
+	return uint((s[i>>5] >> uint(i&31)) & 1)
+}
+
 // IsNonZeroI returns 1 if s is non-zero and 0 otherwise.
 func (s *Scalar) IsNonZeroI() int32 {
 	ret := s[0] | s[1] | s[2] | s[3] | s[4] | s[5] | s[6] | s[7]
@@ -998,6 +1162,28 @@ func (s *Scalar) Equals(a *Scalar) bool {
 	return b.Sub(s, a).IsNonZeroI() == 0
 }
 
+// ScalarInRangeI returns 1 if the little endian number encoded in buf
+// lies in the range [1, l-1] and 0 otherwise.
+//
+// This is useful to validate secret scalars, such as private keys, before
+// they are passed to SetBytes(), which silently reduces them.
+func ScalarInRangeI(buf *[32]byte) int32 {
+	// buf - l underflows iff buf < l.
+	var borrow uint64
+	var nonZero uint32
+	for i := 0; i < 8; i++ {
+		x := load4u32(buf[4*i:])
+		nonZero |= x
+		borrow = uint64(x) - uint64(scL[i]) - (borrow >> 63)
+	}
+	nonZero |= nonZero >> 16
+	nonZero |= nonZero >> 8
+	nonZero |= nonZero >> 4
+	nonZero |= nonZero >> 2
+	nonZero |= nonZero >> 1
+	return int32(uint32(borrow>>63) & nonZero & 1)
+}
+
 // Implements encoding/BinaryUnmarshaler. Use SetBytes, if convenient, instead.
 func (s *Scalar) UnmarshalBinary(data []byte) error {
 	if len(data) != 32 {
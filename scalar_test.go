@@ -1,6 +1,7 @@
 package ristretto_test
 
 import (
+	"bytes"
 	"encoding/base64"
 	"encoding/hex"
 	"math/big"
@@ -9,6 +10,7 @@ import (
 	"testing"
 
 	"github.com/bwesterb/go-ristretto"
+	"github.com/bwesterb/go-ristretto/edwards25519"
 )
 
 var biL big.Int
@@ -154,6 +156,15 @@ func TestScInverse(t *testing.T) {
 		if s2.Inverse(&s1).BigInt().Cmp(&bi2) != 0 {
 			t.Fatalf("1/%v = %v != %v", &bi1, &bi2, &s2)
 		}
+		if !s2.Mul(&s2, &s1).Equals(new(ristretto.Scalar).SetOne()) {
+			t.Fatalf("%v * 1/%[1]v = %v != 1", &bi1, &s2)
+		}
+	}
+
+	// The inverse of zero is defined to be zero
+	s1.SetZero()
+	if !s2.Inverse(&s1).Equals(&s1) {
+		t.Fatalf("1/0 = %v != 0", &s2)
 	}
 }
 
@@ -171,6 +182,275 @@ func TestScNeg(t *testing.T) {
 	}
 }
 
+func TestScDoubleHalve(t *testing.T) {
+	var bi1, bi2 big.Int
+	var s1, s2, s3 ristretto.Scalar
+	for i := 0; i < 1000; i++ {
+		bi1.Rand(rnd, &biL)
+		bi2.Lsh(&bi1, 1)
+		bi2.Mod(&bi2, &biL)
+		s1.SetBigInt(&bi1)
+		if s2.Double(&s1).BigInt().Cmp(&bi2) != 0 {
+			t.Fatalf("2 * %v = %v != %v", &bi1, &bi2, &s2)
+		}
+		if !s3.Halve(&s2).Equals(&s1) {
+			t.Fatalf("Halve(Double(%v)) = %v", &bi1, &s3)
+		}
+		if !s3.Double(s3.Halve(&s1)).Equals(&s1) {
+			t.Fatalf("Double(Halve(%v)) = %v", &bi1, &s3)
+		}
+	}
+}
+
+func TestScalarLimbs(t *testing.T) {
+	var bi, limb, mask big.Int
+	var s ristretto.Scalar
+	var buf [32]byte
+	mask.SetUint64(^uint64(0))
+	for i := 0; i < 1000; i++ {
+		bi.Rand(rnd, &biL)
+		s.SetBigInt(&bi)
+		s.BytesInto(&buf)
+		limbs := ristretto.ScalarToLimbs(&buf)
+		for j := 0; j < 4; j++ {
+			limb.Rsh(&bi, uint(64*j))
+			limb.And(&limb, &mask)
+			if limb.Uint64() != limbs[j] {
+				t.Fatalf("ScalarToLimbs(%v)[%d] = %x", &bi, j, limbs[j])
+			}
+		}
+		if ristretto.ScalarFromLimbs(&limbs) != buf {
+			t.Fatalf("ScalarFromLimbs(ScalarToLimbs(%x)) != %[1]x", buf)
+		}
+	}
+}
+
+func TestScBits(t *testing.T) {
+	var bi big.Int
+	var s, t2 ristretto.Scalar
+	var bits [ristretto.ScalarBits]int8
+	for i := 0; i < 1000; i++ {
+		bi.Rand(rnd, &biL)
+		if i == 0 {
+			bi.Sub(&biL, big.NewInt(1))
+		}
+		s.SetBigInt(&bi)
+		s.Bits(bits[:])
+
+		// Reconstruct s from its bits, most significant first
+		t2.SetZero()
+		for j := ristretto.ScalarBits - 1; j >= 0; j-- {
+			t2.Double(&t2)
+			if bits[j] == 1 {
+				t2.Add(&t2, new(ristretto.Scalar).SetOne())
+			} else if bits[j] != 0 {
+				t.Fatalf("Bits(%v)[%d] = %d", &bi, j, bits[j])
+			}
+			if uint(bi.Bit(j)) != s.BitAt(j) {
+				t.Fatalf("BitAt(%v, %d) = %d", &bi, j, s.BitAt(j))
+			}
+		}
+		if !t2.Equals(&s) {
+			t.Fatalf("Bits(%v) reconstructs to %v", &bi, &t2)
+		}
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("Bits does not panic on a short slice")
+		}
+	}()
+	s.Bits(bits[:ristretto.ScalarBits-1])
+}
+
+func TestScalarInnerProduct(t *testing.T) {
+	var bi, prod, sum big.Int
+	var s, res ristretto.Scalar
+	for n := 0; n < 20; n++ {
+		a := make([]*[32]byte, n)
+		b := make([]*[32]byte, n)
+		sum.SetInt64(0)
+		for i := 0; i < n; i++ {
+			a[i], b[i] = new([32]byte), new([32]byte)
+			bi.Rand(rnd, &biL)
+			s.SetBigInt(&bi).BytesInto(a[i])
+			prod.Rand(rnd, &biL)
+			s.SetBigInt(&prod).BytesInto(b[i])
+			prod.Mul(&prod, &bi)
+			sum.Add(&sum, &prod)
+		}
+		sum.Mod(&sum, &biL)
+		ip, err := ristretto.ScalarInnerProduct(a, b)
+		if err != nil {
+			t.Fatalf("ScalarInnerProduct: %v", err)
+		}
+		if res.SetBytes(ip).BigInt().Cmp(&sum) != 0 {
+			t.Fatalf("ScalarInnerProduct() = %v != %v", res.BigInt(), &sum)
+		}
+		if n > 0 {
+			if _, err := ristretto.ScalarInnerProduct(a, b[1:]); err == nil {
+				t.Fatalf("ScalarInnerProduct accepts mismatched lengths")
+			}
+		}
+	}
+}
+
+func TestScalarPowers(t *testing.T) {
+	var bi, pow big.Int
+	var s ristretto.Scalar
+	var x [32]byte
+	for i := 0; i < 20; i++ {
+		switch i {
+		case 0:
+			bi.SetInt64(0)
+		case 1:
+			bi.SetInt64(1)
+		default:
+			bi.Rand(rnd, &biL)
+		}
+		s.SetBigInt(&bi).BytesInto(&x)
+		for _, n := range []int{0, 1, 2, 7, 64} {
+			powers := ristretto.ScalarPowers(&x, n)
+			if len(powers) != n {
+				t.Fatalf("len(ScalarPowers(%v, %d)) = %d", &bi, n, len(powers))
+			}
+			pow.SetInt64(1)
+			for j := 0; j < n; j++ {
+				if s.SetBytes(&powers[j]).BigInt().Cmp(&pow) != 0 {
+					t.Fatalf("ScalarPowers(%v, %d)[%d] = %v != %v",
+						&bi, n, j, s.BigInt(), &pow)
+				}
+				pow.Mul(&pow, &bi)
+				pow.Mod(&pow, &biL)
+			}
+		}
+	}
+}
+
+func TestScalarsFromUniform(t *testing.T) {
+	var s ristretto.Scalar
+	var block [64]byte
+	var want [32]byte
+	for _, n := range []int{0, 1, 5, 32} {
+		in := make([]byte, 64*n)
+		rnd.Read(in)
+		scalars, err := ristretto.ScalarsFromUniform(in, n)
+		if err != nil {
+			t.Fatalf("ScalarsFromUniform: %v", err)
+		}
+		if len(scalars) != n {
+			t.Fatalf("len(ScalarsFromUniform(.., %d)) = %d", n, len(scalars))
+		}
+		for i := 0; i < n; i++ {
+			copy(block[:], in[64*i:])
+			s.SetReduced(&block).BytesInto(&want)
+			if scalars[i] != want {
+				t.Fatalf("ScalarsFromUniform()[%d] = %x != %x",
+					i, scalars[i], want)
+			}
+		}
+		if _, err := ristretto.ScalarsFromUniform(append(in, 0), n); err == nil {
+			t.Fatalf("ScalarsFromUniform accepts too long input")
+		}
+		if _, err := ristretto.ScalarsFromUniform(in, n+1); err == nil {
+			t.Fatalf("ScalarsFromUniform accepts too short input")
+		}
+	}
+}
+
+// Scalar provides the arithmetic modulo l for the encoded scalars that
+// package edwards25519 works with.
+func TestScalarWithEdwards25519(t *testing.T) {
+	var a, b, c, s ristretto.Scalar
+	var B, lhs, rhs, tmp edwards25519.ExtendedPoint
+	var buf [32]byte
+	B.SetBase()
+	for i := 0; i < 100; i++ {
+		a.Rand()
+		b.Rand()
+		c.Rand()
+
+		// (a*b - c)*B == a*(b*B) - c*B
+		s.MulSub(&a, &b, &c).BytesInto(&buf)
+		lhs.ScalarMult(&B, &buf)
+		b.BytesInto(&buf)
+		rhs.ScalarMult(&B, &buf)
+		a.BytesInto(&buf)
+		rhs.ScalarMult(&rhs, &buf)
+		c.BytesInto(&buf)
+		rhs.Sub(&rhs, tmp.ScalarMult(&B, &buf))
+		if !lhs.Equals(&rhs) {
+			t.Fatalf("(a*b - c)*B != a*(b*B) - c*B for a=%v b=%v c=%v", a, b, c)
+		}
+	}
+}
+
+func TestScRandFrom(t *testing.T) {
+	var s, s2, want ristretto.Scalar
+	var buf [64]byte
+	rnd.Read(buf[:])
+	if _, err := s.RandFrom(bytes.NewReader(buf[:])); err != nil {
+		t.Fatalf("RandFrom: %v", err)
+	}
+	if !s.Equals(want.SetReduced(&buf)) {
+		t.Fatalf("RandFrom() = %v != %v", s, want)
+	}
+
+	if _, err := s2.RandFrom(nil); err != nil {
+		t.Fatalf("RandFrom(nil): %v", err)
+	}
+	if s2.Equals(&s) {
+		t.Fatalf("RandFrom(nil) is not random")
+	}
+
+	// Read errors are propagated and leave s unchanged
+	want.Set(&s)
+	if _, err := s.RandFrom(bytes.NewReader(buf[:63])); err == nil {
+		t.Fatalf("RandFrom does not report a short read")
+	}
+	if !s.Equals(&want) {
+		t.Fatalf("RandFrom changed s on error")
+	}
+}
+
+func TestScSetCanonicalBytes(t *testing.T) {
+	var s1, s2 ristretto.Scalar
+	var buf [32]byte
+	for i := 0; i < 1000; i++ {
+		s1.Rand()
+		s1.BytesInto(&buf)
+		if err := s2.SetCanonicalBytes(&buf); err != nil {
+			t.Fatalf("SetCanonicalBytes(%x): %v", buf, err)
+		}
+		if !s1.Equals(&s2) {
+			t.Fatalf("SetCanonicalBytes(%x) = %v != %v", buf, s2, s1)
+		}
+	}
+
+	// l, l+1 and 2^256-1 are not canonical
+	l := [32]byte{
+		0xed, 0xd3, 0xf5, 0x5c, 0x1a, 0x63, 0x12, 0x58,
+		0xd6, 0x9c, 0xf7, 0xa2, 0xde, 0xf9, 0xde, 0x14,
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x10,
+	}
+	if s2.SetCanonicalBytes(&l) == nil {
+		t.Fatalf("SetCanonicalBytes accepts l")
+	}
+	l[0]++
+	if s2.SetCanonicalBytes(&l) == nil {
+		t.Fatalf("SetCanonicalBytes accepts l+1")
+	}
+	for j := 0; j < 32; j++ {
+		buf[j] = 0xff
+	}
+	if s2.SetCanonicalBytes(&buf) == nil {
+		t.Fatalf("SetCanonicalBytes accepts 2^256-1")
+	}
+	if !s1.Equals(&s2) {
+		t.Fatalf("SetCanonicalBytes changed s on error")
+	}
+}
+
 func TestScReduced(t *testing.T) {
 	var bi1, bi2, bi512 big.Int
 	var s ristretto.Scalar
@@ -191,6 +471,57 @@ func TestScReduced(t *testing.T) {
 
 }
 
+func TestScSetReducedUniform(t *testing.T) {
+	var bi1, bi2 big.Int
+	var s ristretto.Scalar
+	var buf [64]byte
+	var be [64]byte
+	for i := 0; i < 1000; i++ {
+		rnd.Read(buf[:])
+		if i == 0 {
+			for j := range buf {
+				buf[j] = 0xff
+			}
+		}
+		for j := 0; j < 64; j++ {
+			be[63-j] = buf[j]
+		}
+		bi1.SetBytes(be[:])
+		bi2.Mod(&bi1, &biL)
+		if s.SetReduced(&buf).BigInt().Cmp(&bi2) != 0 {
+			t.Fatalf("SetReduced(%v) = %v != %v", &bi1, s.BigInt(), &bi2)
+		}
+	}
+}
+
+func TestScSetWideBytes(t *testing.T) {
+	var bi1, bi2 big.Int
+	var s ristretto.Scalar
+	for _, n := range []int{32, 48, 64} {
+		for i := 0; i < 100; i++ {
+			in := make([]byte, n)
+			rBuf := make([]byte, n)
+			rnd.Read(in)
+			for j := 0; j < n; j++ {
+				rBuf[j] = in[n-j-1]
+			}
+			bi1.SetBytes(rBuf)
+			bi2.Mod(&bi1, &biL)
+			if _, err := s.SetWideBytes(in); err != nil {
+				t.Fatalf("SetWideBytes(%x): %v", in, err)
+			}
+			if s.BigInt().Cmp(&bi2) != 0 {
+				t.Fatalf("SetWideBytes(%v) = %v != %v", &bi1, s.BigInt(), &bi2)
+			}
+		}
+	}
+	for _, n := range []int{0, 31, 65} {
+		if _, err := s.SetWideBytes(make([]byte, n)); err == nil {
+			t.Fatalf("SetWideBytes accepts %d bytes", n)
+		}
+	}
+}
+
 func TestScTextMarshaling(t *testing.T) {
 	var s, s2 ristretto.Scalar
 	for i := 0; i < 100; i++ {
@@ -234,6 +565,48 @@ func TestScDerive(t *testing.T) {
 	}
 }
 
+func TestScalarInRangeI(t *testing.T) {
+	var bi big.Int
+	for _, v := range []struct {
+		delta int64
+		ok    int32
+	}{{-1, 1}, {0, 0}, {1, 0}} {
+		var buf [32]byte
+		bi.SetInt64(v.delta)
+		bi.Add(&bi, &biL)
+		rBuf := bi.Bytes()
+		for j := 0; j < len(rBuf); j++ {
+			buf[j] = rBuf[len(rBuf)-j-1]
+		}
+		if ristretto.ScalarInRangeI(&buf) != v.ok {
+			t.Fatalf("ScalarInRangeI(l%+d) != %d", v.delta, v.ok)
+		}
+	}
+
+	var buf [32]byte
+	if ristretto.ScalarInRangeI(&buf) != 0 {
+		t.Fatalf("ScalarInRangeI(0) != 0")
+	}
+	buf[0] = 1
+	if ristretto.ScalarInRangeI(&buf) != 1 {
+		t.Fatalf("ScalarInRangeI(1) != 1")
+	}
+	for i := range buf {
+		buf[i] = 0xff
+	}
+	if ristretto.ScalarInRangeI(&buf) != 0 {
+		t.Fatalf("ScalarInRangeI(2^256-1) != 0")
+	}
+
+	var s ristretto.Scalar
+	for i := 0; i < 100; i++ {
+		s.Rand().BytesInto(&buf)
+		if ristretto.ScalarInRangeI(&buf) != s.IsNonZeroI() {
+			t.Fatalf("ScalarInRangeI(%v) != %d", s, s.IsNonZeroI())
+		}
+	}
+}
+
 func TestIssue14(t *testing.T) {
 	var buf [32]byte
 	var s ristretto.Scalar
@@ -0,0 +1,80 @@
+package schnorr
+
+import (
+	"errors"
+
+	"github.com/bwesterb/go-ristretto"
+)
+
+// Adaptor signatures.
+//
+// A pre-signature on msg for the adaptor point T = t*B is a pair R || s'
+// with s'*B = R - T + H(R || A || msg)*A.  Anyone who knows t can
+// turn it into the ordinary signature R || s'+t with Adapt().
+// Conversely, anyone who sees both the pre-signature and the signature
+// learns t with Extract().  This is the building block for atomic swaps
+// and scriptless scripts.
+
+// Creates a pre-signature on msg with the private key priv for the
+// adaptor point T.  Returns the 64-byte pre-signature.
+func PreSign(priv *ristretto.Scalar, msg []byte, T *ristretto.Point) []byte {
+	var k, c, s ristretto.Scalar
+	var A, R ristretto.Point
+	k.Rand()
+	A.ScalarMultBase(priv)
+	R.ScalarMultBase(&k)
+	R.Add(&R, T)
+	challenge(&c, &R, &A, msg)
+	s.MulAdd(&c, priv, &k)
+	return pack(&R, &s)
+}
+
+// Returns whether presig is a valid pre-signature created with PreSign()
+// on msg for the public key pub and adaptor point T.
+//
+// The counterparty should check this before relying on being able to
+// Extract() the discrete logarithm of T later on.
+func VerifyPreSignature(pub *ristretto.Point, msg []byte, T *ristretto.Point,
+	presig []byte) bool {
+	var R, R2, cA ristretto.Point
+	var s, c ristretto.Scalar
+	if !unpack(presig, &R, &s) {
+		return false
+	}
+	challenge(&c, &R, pub, msg)
+	R2.PublicScalarMultBase(&s)
+	R2.Sub(&R2, cA.PublicScalarMult(pub, &c))
+	R2.Add(&R2, T)
+	return R.Equals(&R2)
+}
+
+// Completes the pre-signature presig using the discrete logarithm t of
+// its adaptor point.  Returns the signature, which can be checked with
+// Verify().
+func Adapt(presig []byte, t *ristretto.Scalar) ([]byte, error) {
+	var R ristretto.Point
+	var s ristretto.Scalar
+	if !unpack(presig, &R, &s) {
+		return nil, errors.New("Malformed pre-signature")
+	}
+	s.Add(&s, t)
+	return pack(&R, &s), nil
+}
+
+// Recovers the discrete logarithm of the adaptor point from the
+// pre-signature presig and the signature sig created from it with Adapt().
+func Extract(presig, sig []byte) (t ristretto.Scalar, err error) {
+	var R1, R2 ristretto.Point
+	var s1, s2 ristretto.Scalar
+	if !unpack(presig, &R1, &s1) {
+		return t, errors.New("Malformed pre-signature")
+	}
+	if !unpack(sig, &R2, &s2) {
+		return t, errors.New("Malformed signature")
+	}
+	if !R1.Equals(&R2) {
+		return t, errors.New("Signature was not adapted from pre-signature")
+	}
+	t.Sub(&s2, &s1)
+	return t, nil
+}
@@ -0,0 +1,55 @@
+package schnorr_test
+
+import (
+	"testing"
+
+	"github.com/bwesterb/go-ristretto"
+	"github.com/bwesterb/go-ristretto/schnorr"
+)
+
+func TestAdaptorSignature(t *testing.T) {
+	var priv, adaptor, wrong ristretto.Scalar
+	var pub, T ristretto.Point
+	msg := []byte("swap 1 coin")
+	for i := 0; i < 100; i++ {
+		priv.Rand()
+		pub.ScalarMultBase(&priv)
+		adaptor.Rand()
+		T.ScalarMultBase(&adaptor)
+
+		presig := schnorr.PreSign(&priv, msg, &T)
+		if !schnorr.VerifyPreSignature(&pub, msg, &T, presig) {
+			t.Fatalf("Pre-signature does not verify")
+		}
+		if schnorr.Verify(&pub, msg, presig) {
+			t.Fatalf("Pre-signature verifies as a signature")
+		}
+
+		sig, err := schnorr.Adapt(presig, &adaptor)
+		if err != nil {
+			t.Fatalf("Adapt: %v", err)
+		}
+		if !schnorr.Verify(&pub, msg, sig) {
+			t.Fatalf("Adapted signature does not verify")
+		}
+
+		extracted, err := schnorr.Extract(presig, sig)
+		if err != nil {
+			t.Fatalf("Extract: %v", err)
+		}
+		if !extracted.Equals(&adaptor) {
+			t.Fatalf("Extract() = %v != %v", extracted, adaptor)
+		}
+
+		wrong.Rand()
+		sig, _ = schnorr.Adapt(presig, &wrong)
+		if schnorr.Verify(&pub, msg, sig) {
+			t.Fatalf("Signature adapted with wrong secret verifies")
+		}
+	}
+
+	presig := schnorr.PreSign(&priv, msg, &T)
+	if _, err := schnorr.Extract(presig, schnorr.Sign(&priv, msg)); err == nil {
+		t.Fatalf("Extract from unrelated signature succeeded")
+	}
+}
@@ -0,0 +1,35 @@
+package schnorr
+
+import (
+	"fmt"
+
+	"github.com/bwesterb/go-ristretto"
+)
+
+// Checks the encodings of a batch of public keys and signatures before
+// verifying them.  pubs[i] is the public key for sigs[i].  Returns the
+// indices of the entries whose public key encodes a point and whose
+// signature is well-formed, that is: of the right length, with a valid
+// point R and a canonically encoded scalar s.
+//
+// This separates parsing errors from cryptographic failures: an entry
+// that is not returned cannot be valid, but an entry that is returned
+// still needs to be verified.  Returns an error if pubs and sigs have
+// different lengths.
+func PrevalidateBatch(pubs [][32]byte, sigs [][]byte) (okIndices []int, err error) {
+	var A, R ristretto.Point
+	var s ristretto.Scalar
+	if len(pubs) != len(sigs) {
+		return nil, fmt.Errorf(
+			"Number of public keys (%d) and signatures (%d) differ",
+			len(pubs), len(sigs))
+	}
+	okIndices = make([]int, 0, len(pubs))
+	for i := 0; i < len(pubs); i++ {
+		if !A.SetBytes(&pubs[i]) || !unpack(sigs[i], &R, &s) {
+			continue
+		}
+		okIndices = append(okIndices, i)
+	}
+	return okIndices, nil
+}
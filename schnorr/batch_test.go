@@ -0,0 +1,47 @@
+package schnorr_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/bwesterb/go-ristretto"
+	"github.com/bwesterb/go-ristretto/schnorr"
+)
+
+func TestPrevalidateBatch(t *testing.T) {
+	var priv ristretto.Scalar
+	var pub ristretto.Point
+	msg := []byte("test message")
+	pubs := make([][32]byte, 8)
+	sigs := make([][]byte, 8)
+	for i := 0; i < len(pubs); i++ {
+		priv.Rand()
+		pub.ScalarMultBase(&priv)
+		pub.BytesInto(&pubs[i])
+		sigs[i] = schnorr.Sign(&priv, msg)
+	}
+
+	// A valid signature for the wrong message is still well-formed.
+	sigs[1] = schnorr.Sign(&priv, []byte("other message"))
+
+	// Malformed entries
+	pubs[2][0] |= 1        // odd encoding: not a point
+	sigs[3] = sigs[3][:63] // truncated
+	sigs[4][0] |= 1        // R is not a point
+	sigs[5][63] = 0xff     // s is not reduced
+	for j := 0; j < 32; j++ {
+		pubs[6][j] = 0xff // not canonical
+	}
+
+	okIndices, err := schnorr.PrevalidateBatch(pubs, sigs)
+	if err != nil {
+		t.Fatalf("PrevalidateBatch: %v", err)
+	}
+	if !reflect.DeepEqual(okIndices, []int{0, 1, 7}) {
+		t.Fatalf("PrevalidateBatch() = %v != [0 1 7]", okIndices)
+	}
+
+	if _, err := schnorr.PrevalidateBatch(pubs, sigs[:7]); err == nil {
+		t.Fatalf("PrevalidateBatch accepted batches of different lengths")
+	}
+}
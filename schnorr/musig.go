@@ -0,0 +1,77 @@
+package schnorr
+
+import (
+	"github.com/bwesterb/go-ristretto"
+)
+
+// MuSig-style multi-signatures.
+//
+// n parties with public keys X_1, ..., X_n compute the aggregate public key
+// X = a_1*X_1 + ... + a_n*X_n with AggregatePublicKeys(), where the
+// coefficient a_i = H(X_1 || ... || X_n || X_i) prevents rogue-key attacks.
+// To sign msg, every party i picks a secret nonce k_i and publishes
+// R_i = k_i*B.  With R = R_1 + ... + R_n, each party computes its partial
+// signature with PartialSign() and anyone can combine them into an ordinary
+// signature on msg for X with CombinePartialSignatures().
+//
+// The nonces must be fresh and secret for every signing session.  To prevent
+// a party from choosing its R_i after seeing the others, the parties should
+// first exchange commitments to their R_i, as in the MuSig paper.
+
+// Domain separation string for the key aggregation coefficients.
+const musigCoefDomain = "go-ristretto musig coefficient v1"
+
+// Returns the aggregate public key of pubs and the coefficient a_i of
+// each public key in the aggregate.  The order of pubs matters.
+func AggregatePublicKeys(pubs []*ristretto.Point) (aggPub ristretto.Point,
+	coefs []ristretto.Scalar) {
+	var buf [32]byte
+	var tmp ristretto.Point
+	prefix := make([]byte, 0, len(musigCoefDomain)+32*(len(pubs)+1))
+	prefix = append(prefix, musigCoefDomain...)
+	for _, pub := range pubs {
+		pub.BytesInto(&buf)
+		prefix = append(prefix, buf[:]...)
+	}
+
+	coefs = make([]ristretto.Scalar, len(pubs))
+	aggPub.SetZero()
+	for i, pub := range pubs {
+		pub.BytesInto(&buf)
+		coefs[i].Derive(append(prefix[:len(prefix):len(prefix)], buf[:]...))
+		aggPub.Add(&aggPub, tmp.ScalarMult(pub, &coefs[i]))
+	}
+	return
+}
+
+// Returns the partial signature k + c*a*x on msg of the party with private
+// key priv, key aggregation coefficient coef and secret nonce, where R is
+// the sum of the nonce commitments of all parties and aggPub the aggregate
+// public key.
+func PartialSign(priv, coef, nonce *ristretto.Scalar, R, aggPub *ristretto.Point,
+	msg []byte) ristretto.Scalar {
+	var c, ax, s ristretto.Scalar
+	challenge(&c, R, aggPub, msg)
+	ax.Mul(coef, priv)
+	s.MulAdd(&c, &ax, nonce)
+	return s
+}
+
+// Combines the partial signatures created with PartialSign() into a
+// signature on the aggregate public key, where R is the sum of the nonce
+// commitments.  Returns the 64-byte signature.
+func CombinePartialSignatures(R *ristretto.Point, partials []ristretto.Scalar) []byte {
+	var s ristretto.Scalar
+	s.SetZero()
+	for i := 0; i < len(partials); i++ {
+		s.Add(&s, &partials[i])
+	}
+	return pack(R, &s)
+}
+
+// Returns whether aggSig is a valid aggregated signature on msg for the
+// aggregate public key aggPub.  An aggregated signature is an ordinary
+// signature for the aggregate key, so this is the same as Verify().
+func VerifyAggregate(aggPub *ristretto.Point, msg []byte, aggSig []byte) bool {
+	return Verify(aggPub, msg, aggSig)
+}
@@ -0,0 +1,54 @@
+package schnorr_test
+
+import (
+	"testing"
+
+	"github.com/bwesterb/go-ristretto"
+	"github.com/bwesterb/go-ristretto/schnorr"
+)
+
+func TestVerifyAggregate(t *testing.T) {
+	var privs, nonces [3]ristretto.Scalar
+	var pubs [3]*ristretto.Point
+	var R, Ri ristretto.Point
+	msg := []byte("spend 1 coin")
+	for i := 0; i < 100; i++ {
+		R.SetZero()
+		for j := 0; j < 3; j++ {
+			privs[j].Rand()
+			pubs[j] = new(ristretto.Point).ScalarMultBase(&privs[j])
+			nonces[j].Rand()
+			R.Add(&R, Ri.ScalarMultBase(&nonces[j]))
+		}
+		aggPub, coefs := schnorr.AggregatePublicKeys(pubs[:])
+
+		partials := make([]ristretto.Scalar, 3)
+		for j := 0; j < 3; j++ {
+			partials[j] = schnorr.PartialSign(&privs[j], &coefs[j],
+				&nonces[j], &R, &aggPub, msg)
+		}
+		sig := schnorr.CombinePartialSignatures(&R, partials)
+		if !schnorr.VerifyAggregate(&aggPub, msg, sig) {
+			t.Fatalf("Aggregate signature does not verify")
+		}
+		if schnorr.VerifyAggregate(&aggPub, []byte("spend 2 coins"), sig) {
+			t.Fatalf("Aggregate signature verifies for another message")
+		}
+
+		// The aggregate key depends on the order of the keys
+		swapped := []*ristretto.Point{pubs[1], pubs[0], pubs[2]}
+		aggPub2, _ := schnorr.AggregatePublicKeys(swapped)
+		if schnorr.VerifyAggregate(&aggPub2, msg, sig) {
+			t.Fatalf("Aggregate signature verifies for reordered keys")
+		}
+
+		// One party signs with a different key
+		privs[1].Rand()
+		partials[1] = schnorr.PartialSign(&privs[1], &coefs[1],
+			&nonces[1], &R, &aggPub, msg)
+		sig = schnorr.CombinePartialSignatures(&R, partials)
+		if schnorr.VerifyAggregate(&aggPub, msg, sig) {
+			t.Fatalf("Aggregate signature with altered contribution verifies")
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package schnorr
+
+import (
+	"errors"
+
+	"github.com/bwesterb/go-ristretto"
+)
+
+// Domain separation strings used for the challenge and the nonce of
+// recoverable signatures.
+const (
+	recoverableChallengeDomain = "go-ristretto schnorr recoverable v1"
+	recoverableNonceDomain     = "go-ristretto schnorr recoverable nonce v1"
+)
+
+// Signs msg with the private key priv such that the public key can be
+// recovered from the signature with RecoverPublicKey().  Returns the
+// 64-byte signature R || s.  Like Sign(), the nonce is derived
+// deterministically from priv and msg, but under its own domain separation
+// string.  Otherwise the signatures of Sign() and SignRecoverable() on the
+// same message would share R under different challenges, which reveals
+// priv.
+func SignRecoverable(priv *ristretto.Scalar, msg []byte) []byte {
+	var k, c, s ristretto.Scalar
+	var R ristretto.Point
+	var buf [32]byte
+	priv.BytesInto(&buf)
+	buf = deterministicNonce(recoverableNonceDomain, &buf, msg)
+	k.SetBytes(&buf)
+	R.ScalarMultBase(&k)
+	recoverableChallenge(&c, &R, msg)
+	s.MulAdd(&c, priv, &k)
+	return pack(&R, &s)
+}
+
+// Returns the public key for which sig, created with SignRecoverable(), is
+// a valid signature on msg.
+//
+// A signature R || s created by Sign() cannot be used to recover the
+// public key A, because A is hashed into the challenge H(R || A || msg):
+// solving s*B = R + c*A for A requires c, which requires A.  Recovery is
+// only possible if the challenge does not depend on A.  A recoverable
+// signature therefore uses the challenge c = H'(R || msg), with a separate
+// domain separation string, so that
+//
+//	A = c^-1 * (s*B - R).
+//
+// This saves transmitting the public key, for instance in compact
+// certificates, but the public key is no longer bound to the signature:
+// for any R || s and msg there is some A for which it verifies.  A
+// recovered key is thus only meaningful when compared to a key that is
+// trusted by other means, and recoverable signatures should not be mixed
+// with protocols that rely on key prefixing, such as MuSig.
+//
+// Returns an error if sig is malformed, if its challenge happens to be
+// zero, or if the recovered key is zero.
+func RecoverPublicKey(msg []byte, sig []byte) (*ristretto.Point, error) {
+	var R, A ristretto.Point
+	var s, c ristretto.Scalar
+	if !unpack(sig, &R, &s) {
+		return nil, errors.New("Malformed signature")
+	}
+	recoverableChallenge(&c, &R, msg)
+	if c.IsNonZeroI() == 0 {
+		return nil, errors.New("Challenge is zero")
+	}
+	A.PublicScalarMultBase(&s)
+	A.Sub(&A, &R)
+	A.PublicScalarMult(&A, c.Inverse(&c))
+	if A.Equals(new(ristretto.Point).SetZero()) {
+		return nil, errors.New("Recovered public key is zero")
+	}
+	return &A, nil
+}
+
+// Sets c to the challenge H'(R || msg) of a recoverable signature.
+// Returns c.
+func recoverableChallenge(c *ristretto.Scalar, R *ristretto.Point,
+	msg []byte) *ristretto.Scalar {
+	var buf [32]byte
+	transcript := make([]byte, 0, len(recoverableChallengeDomain)+32+len(msg))
+	transcript = append(transcript, recoverableChallengeDomain...)
+	R.BytesInto(&buf)
+	transcript = append(transcript, buf[:]...)
+	transcript = append(transcript, msg...)
+	return c.Derive(transcript)
+}
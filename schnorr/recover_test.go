@@ -0,0 +1,57 @@
+package schnorr_test
+
+import (
+	"testing"
+
+	"github.com/bwesterb/go-ristretto"
+	"github.com/bwesterb/go-ristretto/schnorr"
+)
+
+func TestRecoverPublicKey(t *testing.T) {
+	var priv ristretto.Scalar
+	var pub ristretto.Point
+	msg := []byte("certificate")
+	for i := 0; i < 100; i++ {
+		priv.Rand()
+		pub.ScalarMultBase(&priv)
+		sig := schnorr.SignRecoverable(&priv, msg)
+		got, err := schnorr.RecoverPublicKey(msg, sig)
+		if err != nil {
+			t.Fatalf("RecoverPublicKey: %v", err)
+		}
+		if !got.Equals(&pub) {
+			t.Fatalf("RecoverPublicKey() = %v != %v", got, &pub)
+		}
+
+		// A different message gives a different key
+		got, err = schnorr.RecoverPublicKey([]byte("other"), sig)
+		if err != nil {
+			t.Fatalf("RecoverPublicKey: %v", err)
+		}
+		if got.Equals(&pub) {
+			t.Fatalf("RecoverPublicKey ignores the message")
+		}
+
+		// Recoverable signatures are not ordinary signatures
+		if schnorr.Verify(&pub, msg, sig) {
+			t.Fatalf("Recoverable signature verifies as ordinary signature")
+		}
+	}
+
+	if _, err := schnorr.RecoverPublicKey(msg, make([]byte, 10)); err == nil {
+		t.Fatalf("RecoverPublicKey accepts a short signature")
+	}
+}
+
+func TestSignRecoverableNonce(t *testing.T) {
+	var priv ristretto.Scalar
+	msg := []byte("certificate")
+	for i := 0; i < 10; i++ {
+		priv.Rand()
+		sig := schnorr.Sign(&priv, msg)
+		rsig := schnorr.SignRecoverable(&priv, msg)
+		if string(sig[:32]) == string(rsig[:32]) {
+			t.Fatalf("Sign and SignRecoverable use the same nonce")
+		}
+	}
+}
@@ -0,0 +1,217 @@
+// Schnorr signatures over the Ristretto group.
+//
+// A private key is a Scalar a and the corresponding public key is the
+// Point A = a*B, where B is the basepoint.  A signature on a message msg
+// is the 64-byte string R || s, where R is an encoded Point and s an encoded
+// Scalar, such that
+//
+//	s*B = R + H(R || A || msg)*A.
+//
+// Here H hashes with SHA512 (prefixed by a domain separation string)
+// and reduces modulo the group order with Scalar.Derive().
+package schnorr
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha512"
+	"errors"
+	"io"
+
+	"github.com/bwesterb/go-ristretto"
+)
+
+// Size of a signature in bytes.
+const SignatureSize = 64
+
+// Errors returned by ParseSignature().
+var (
+	ErrSignatureSize         = errors.New("Signature has the wrong length")
+	ErrSignatureNonCanonical = errors.New("Signature scalar is not canonically encoded")
+	ErrSignatureInvalidPoint = errors.New("Signature commitment does not encode a point")
+)
+
+// Domain separation string used when computing the challenge.
+const challengeDomain = "go-ristretto schnorr v1"
+
+// Domain separation string used when computing deterministic nonces.
+const nonceDomain = "go-ristretto schnorr nonce v1"
+
+// Generates a private key from rng, or from crypto/rand if rng is nil,
+// and returns it together with the corresponding public key.
+func GenerateKey(rng io.Reader) (priv *ristretto.Scalar, pub *ristretto.Point,
+	err error) {
+	priv, err = new(ristretto.Scalar).RandFrom(rng)
+	if err != nil {
+		return nil, nil, err
+	}
+	return priv, new(ristretto.Point).ScalarMultBase(priv), nil
+}
+
+// Signs msg with the private key priv.  Returns the 64-byte signature.
+//
+// The nonce is derived deterministically from priv and msg with
+// DeterministicNonce(), so signing the same message twice gives the same
+// signature.  Use SignRandomized() for a random nonce instead.
+func Sign(priv *ristretto.Scalar, msg []byte) []byte {
+	var k ristretto.Scalar
+	var buf [32]byte
+	priv.BytesInto(&buf)
+	buf = DeterministicNonce(&buf, msg)
+	k.SetBytes(&buf)
+	return sign(priv, &k, msg)
+}
+
+// Signs msg with the private key priv using a nonce drawn from rng, or from
+// crypto/rand if rng is nil.  Returns the 64-byte signature.
+func SignRandomized(priv *ristretto.Scalar, msg []byte, rng io.Reader) ([]byte, error) {
+	var k ristretto.Scalar
+	var buf [64]byte
+	if rng == nil {
+		rng = rand.Reader
+	}
+	if _, err := io.ReadFull(rng, buf[:]); err != nil {
+		return nil, err
+	}
+	k.SetReduced(&buf)
+	return sign(priv, &k, msg), nil
+}
+
+// Returns the nonce for signing msg with the encoded private key priv.
+//
+// The nonce is HMAC-SHA512 keyed with priv over a domain separation string
+// and msg, reduced modulo the group order, in the spirit of RFC 6979.  As it
+// is a pseudorandom function of the key and message, a nonce is only ever
+// reused for the same message, which yields the same signature.
+func DeterministicNonce(priv *[32]byte, msg []byte) [32]byte {
+	return deterministicNonce(nonceDomain, priv, msg)
+}
+
+// Returns the nonce of DeterministicNonce() under the given domain
+// separation string.  Signatures with different challenges on the same
+// message must use different domains: the same nonce under two challenges
+// reveals the private key.
+func deterministicNonce(domain string, priv *[32]byte, msg []byte) [32]byte {
+	var h [64]byte
+	var k ristretto.Scalar
+	var ret [32]byte
+	mac := hmac.New(sha512.New, priv[:])
+	mac.Write([]byte(domain))
+	mac.Write(msg)
+	mac.Sum(h[:0])
+	k.SetReduced(&h).BytesInto(&ret)
+	return ret
+}
+
+// Signs msg with the private key priv and nonce k.
+func sign(priv, k *ristretto.Scalar, msg []byte) []byte {
+	var c, s ristretto.Scalar
+	var A, R ristretto.Point
+	A.ScalarMultBase(priv)
+	R.ScalarMultBase(k)
+	challenge(&c, &R, &A, msg)
+	s.MulAdd(&c, priv, k)
+	return pack(&R, &s)
+}
+
+// Returns whether sig is a valid signature on msg for the public key pub.
+func Verify(pub *ristretto.Point, msg []byte, sig []byte) bool {
+	var R ristretto.Point
+	var s, c ristretto.Scalar
+	if !unpack(sig, &R, &s) {
+		return false
+	}
+	challenge(&c, &R, pub, msg)
+	return verifyEquation(&s, &c, pub, &R)
+}
+
+// Returns whether s*B - c*A == R for the encoded response s, public key A,
+// challenge c and commitment R.  This is the verification equation of a
+// signature R || s with the challenge c computed by the caller.  Returns
+// false if A or R does not encode a point or s is not canonically encoded.
+func VerifyResponseEncoding(s, Abytes, c, Rbytes *[32]byte) bool {
+	var A, R ristretto.Point
+	var sS, cS ristretto.Scalar
+	if !A.SetBytes(Abytes) || !R.SetBytes(Rbytes) {
+		return false
+	}
+	if sS.SetCanonicalBytes(s) != nil {
+		return false
+	}
+	cS.SetBytes(c)
+	return verifyEquation(&sS, &cS, &A, &R)
+}
+
+// Returns whether s*B - c*A == R.
+func verifyEquation(s, c *ristretto.Scalar, A, R *ristretto.Point) bool {
+	var R2 ristretto.Point
+	var cNeg ristretto.Scalar
+	R2.PublicDoubleScalarMultBase(s, cNeg.Neg(c), A)
+	return R.Equals(&R2)
+}
+
+// Sets c to the challenge H(R || A || msg).  Returns c.
+func challenge(c *ristretto.Scalar, R, A *ristretto.Point, msg []byte) *ristretto.Scalar {
+	var buf [32]byte
+	transcript := make([]byte, 0, len(challengeDomain)+64+len(msg))
+	transcript = append(transcript, challengeDomain...)
+	R.BytesInto(&buf)
+	transcript = append(transcript, buf[:]...)
+	A.BytesInto(&buf)
+	transcript = append(transcript, buf[:]...)
+	transcript = append(transcript, msg...)
+	return c.Derive(transcript)
+}
+
+// Returns the signature R || s.
+func pack(R *ristretto.Point, s *ristretto.Scalar) []byte {
+	var buf [32]byte
+	ret := make([]byte, SignatureSize)
+	R.BytesInto(&buf)
+	copy(ret[:32], buf[:])
+	s.BytesInto(&buf)
+	copy(ret[32:], buf[:])
+	return ret
+}
+
+// Splits the signature sig into the encoded commitment R and the encoded
+// scalar s.  Returns ErrSignatureSize if sig is not 64 bytes,
+// ErrSignatureNonCanonical if s is not canonically encoded and
+// ErrSignatureInvalidPoint if R does not encode a point.
+//
+// Verify() performs these checks before any scalar multiplication.
+func ParseSignature(sig []byte) (R [32]byte, s [32]byte, err error) {
+	var RP ristretto.Point
+	var sS ristretto.Scalar
+	if err = parse(sig, &RP, &sS); err != nil {
+		return R, s, err
+	}
+	copy(R[:], sig[:32])
+	copy(s[:], sig[32:])
+	return R, s, nil
+}
+
+// Sets R and s from the signature R || s.  Returns false if sig has the
+// wrong length, R does not encode a point or s is not canonically encoded.
+func unpack(sig []byte, R *ristretto.Point, s *ristretto.Scalar) bool {
+	return parse(sig, R, s) == nil
+}
+
+// Sets R and s from the signature R || s.  Returns the error of
+// ParseSignature() if sig is malformed.  The scalar is checked first, as
+// that is cheaper than decoding the point.
+func parse(sig []byte, R *ristretto.Point, s *ristretto.Scalar) error {
+	var buf [32]byte
+	if len(sig) != SignatureSize {
+		return ErrSignatureSize
+	}
+	copy(buf[:], sig[32:])
+	if s.SetCanonicalBytes(&buf) != nil {
+		return ErrSignatureNonCanonical
+	}
+	copy(buf[:], sig[:32])
+	if !R.SetBytes(&buf) {
+		return ErrSignatureInvalidPoint
+	}
+	return nil
+}
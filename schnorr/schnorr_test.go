@@ -0,0 +1,214 @@
+package schnorr_test
+
+import (
+	"bytes"
+	"crypto/rand"
+	"testing"
+
+	"github.com/bwesterb/go-ristretto"
+	"github.com/bwesterb/go-ristretto/schnorr"
+)
+
+func TestSignVerify(t *testing.T) {
+	var priv ristretto.Scalar
+	var pub, other ristretto.Point
+	msg := []byte("test message")
+	for i := 0; i < 100; i++ {
+		priv.Rand()
+		pub.ScalarMultBase(&priv)
+		sig := schnorr.Sign(&priv, msg)
+		if !schnorr.Verify(&pub, msg, sig) {
+			t.Fatalf("Signature %x does not verify", sig)
+		}
+		if schnorr.Verify(&pub, []byte("other message"), sig) {
+			t.Fatalf("Signature verifies for other message")
+		}
+		other.Rand()
+		if schnorr.Verify(&other, msg, sig) {
+			t.Fatalf("Signature verifies for other public key")
+		}
+		sig[40] ^= 1
+		if schnorr.Verify(&pub, msg, sig) {
+			t.Fatalf("Tampered signature verifies")
+		}
+		if schnorr.Verify(&pub, msg, sig[:63]) {
+			t.Fatalf("Truncated signature verifies")
+		}
+	}
+}
+
+func BenchmarkSign(b *testing.B) {
+	var priv ristretto.Scalar
+	priv.Rand()
+	msg := []byte("test message")
+	for n := 0; n < b.N; n++ {
+		schnorr.Sign(&priv, msg)
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	var priv ristretto.Scalar
+	var pub ristretto.Point
+	priv.Rand()
+	pub.ScalarMultBase(&priv)
+	msg := []byte("test message")
+	sig := schnorr.Sign(&priv, msg)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		schnorr.Verify(&pub, msg, sig)
+	}
+}
+
+func TestVerifyResponseEncoding(t *testing.T) {
+	var priv, c ristretto.Scalar
+	var pub ristretto.Point
+	var A, R, s, cBuf [32]byte
+	msg := []byte("test message")
+	for i := 0; i < 100; i++ {
+		priv.Rand()
+		pub.ScalarMultBase(&priv)
+		pub.BytesInto(&A)
+		sig := schnorr.Sign(&priv, msg)
+		copy(R[:], sig[:32])
+		copy(s[:], sig[32:])
+
+		// Recompute the challenge H(R || A || msg) of the signer.
+		transcript := append([]byte("go-ristretto schnorr v1"), R[:]...)
+		transcript = append(transcript, A[:]...)
+		transcript = append(transcript, msg...)
+		c.Derive(transcript).BytesInto(&cBuf)
+
+		if !schnorr.VerifyResponseEncoding(&s, &A, &cBuf, &R) {
+			t.Fatalf("VerifyResponseEncoding rejects a valid signature")
+		}
+		cBuf[0] ^= 1
+		if schnorr.VerifyResponseEncoding(&s, &A, &cBuf, &R) {
+			t.Fatalf("VerifyResponseEncoding accepts the wrong challenge")
+		}
+		cBuf[0] ^= 1
+		R[0] |= 1
+		if schnorr.VerifyResponseEncoding(&s, &A, &cBuf, &R) {
+			t.Fatalf("VerifyResponseEncoding accepts an invalid R")
+		}
+	}
+}
+
+func TestDeterministicNonce(t *testing.T) {
+	var priv ristretto.Scalar
+	var buf [32]byte
+	for i := 0; i < 100; i++ {
+		priv.Rand()
+		priv.BytesInto(&buf)
+		k := schnorr.DeterministicNonce(&buf, []byte("message"))
+		if k != schnorr.DeterministicNonce(&buf, []byte("message")) {
+			t.Fatalf("DeterministicNonce is not deterministic")
+		}
+		if k == schnorr.DeterministicNonce(&buf, []byte("massage")) {
+			t.Fatalf("DeterministicNonce ignores the message")
+		}
+		if ristretto.ScalarInRangeI(&k) != 1 {
+			t.Fatalf("DeterministicNonce() = %x is not reduced", k)
+		}
+		buf[0] ^= 1
+		if k == schnorr.DeterministicNonce(&buf, []byte("message")) {
+			t.Fatalf("DeterministicNonce ignores the key")
+		}
+	}
+}
+
+func TestGenerateKey(t *testing.T) {
+	var want ristretto.Point
+	msg := []byte("test message")
+	priv, pub, err := schnorr.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	if !pub.Equals(want.ScalarMultBase(priv)) {
+		t.Fatalf("GenerateKey: public key %v does not match", pub)
+	}
+	if !schnorr.Verify(pub, msg, schnorr.Sign(priv, msg)) {
+		t.Fatalf("Signature with generated key does not verify")
+	}
+	priv2, _, err := schnorr.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("GenerateKey: %v", err)
+	}
+	if priv2.Equals(priv) {
+		t.Fatalf("GenerateKey is deterministic")
+	}
+	if _, _, err := schnorr.GenerateKey(bytes.NewReader(nil)); err == nil {
+		t.Fatalf("GenerateKey ignores a failing reader")
+	}
+}
+
+func TestSignRandomized(t *testing.T) {
+	var priv ristretto.Scalar
+	var pub ristretto.Point
+	msg := []byte("test message")
+	priv.Rand()
+	pub.ScalarMultBase(&priv)
+	if !bytes.Equal(schnorr.Sign(&priv, msg), schnorr.Sign(&priv, msg)) {
+		t.Fatalf("Sign is not deterministic")
+	}
+	sig1, err := schnorr.SignRandomized(&priv, msg, nil)
+	if err != nil {
+		t.Fatalf("SignRandomized: %v", err)
+	}
+	sig2, err := schnorr.SignRandomized(&priv, msg, rand.Reader)
+	if err != nil {
+		t.Fatalf("SignRandomized: %v", err)
+	}
+	if bytes.Equal(sig1, sig2) {
+		t.Fatalf("SignRandomized is deterministic")
+	}
+	if !schnorr.Verify(&pub, msg, sig1) || !schnorr.Verify(&pub, msg, sig2) {
+		t.Fatalf("Randomized signature does not verify")
+	}
+	if _, err := schnorr.SignRandomized(&priv, msg, bytes.NewReader(nil)); err == nil {
+		t.Fatalf("SignRandomized succeeds with an empty rng")
+	}
+}
+
+func TestParseSignature(t *testing.T) {
+	var priv ristretto.Scalar
+	priv.Rand()
+	sig := schnorr.Sign(&priv, []byte("message"))
+	if len(sig) != schnorr.SignatureSize {
+		t.Fatalf("Signature has length %d", len(sig))
+	}
+	R, s, err := schnorr.ParseSignature(sig)
+	if err != nil {
+		t.Fatalf("ParseSignature: %v", err)
+	}
+	if !bytes.Equal(R[:], sig[:32]) || !bytes.Equal(s[:], sig[32:]) {
+		t.Fatalf("ParseSignature: (%x, %x) != %x", R, s, sig)
+	}
+
+	// The group order l, which is a non-canonical encoding of zero
+	l := [32]byte{
+		0xed, 0xd3, 0xf5, 0x5c, 0x1a, 0x63, 0x12, 0x58,
+		0xd6, 0x9c, 0xf7, 0xa2, 0xde, 0xf9, 0xde, 0x14,
+		0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0x10,
+	}
+	nonCanonical := append(append([]byte{}, sig[:32]...), l[:]...)
+	invalidR := append(bytes.Repeat([]byte{0xff}, 32), sig[32:]...)
+
+	for _, tc := range []struct {
+		sig []byte
+		err error
+	}{
+		{sig[:63], schnorr.ErrSignatureSize},
+		{append(sig, 0), schnorr.ErrSignatureSize},
+		{nil, schnorr.ErrSignatureSize},
+		{nonCanonical, schnorr.ErrSignatureNonCanonical},
+		{invalidR, schnorr.ErrSignatureInvalidPoint},
+	} {
+		if _, _, err := schnorr.ParseSignature(tc.sig); err != tc.err {
+			t.Fatalf("ParseSignature(%x): %v != %v", tc.sig, err, tc.err)
+		}
+		if schnorr.Verify(new(ristretto.Point).ScalarMultBase(&priv),
+			[]byte("message"), tc.sig) {
+			t.Fatalf("Malformed signature %x verifies", tc.sig)
+		}
+	}
+}
@@ -0,0 +1,73 @@
+// Message construction of the SPAKE2 password-authenticated key exchange
+// over the Ristretto group.
+//
+// Both parties share a password from which they derive the Scalar w.
+// The client picks a random Scalar x and sends X = x*B + w*M; the server
+// picks a random Scalar y and sends Y = y*B + w*N.  Here B is the
+// basepoint and M and N are fixed points of which nobody knows the
+// discrete logarithm.  Both compute the shared element
+// K = x*(Y - w*N) = y*(X - w*M) = x*y*B, which only agrees if they used
+// the same password.  The transcript and K should then be hashed into
+// the session key, which is out of scope for this package.
+package spake2
+
+import (
+	"github.com/bwesterb/go-ristretto"
+)
+
+// Domain separation strings for M, N and the password scalar.
+const (
+	mDomain        = "go-ristretto SPAKE2 M"
+	nDomain        = "go-ristretto SPAKE2 N"
+	passwordDomain = "go-ristretto SPAKE2 password"
+)
+
+// Returns the constant M used to blind the message of the client.
+func M() *ristretto.Point {
+	return new(ristretto.Point).DeriveDalek([]byte(mDomain))
+}
+
+// Returns the constant N used to blind the message of the server.
+func N() *ristretto.Point {
+	return new(ristretto.Point).DeriveDalek([]byte(nDomain))
+}
+
+// Returns the message x*B + w*M of the client if server is false, and
+// the message x*B + w*N of the server if server is true, where w is
+// derived from pw.
+//
+// pw is hashed with SHA-512 only: to protect against offline guessing if
+// it is stored, it should be the output of a password hashing function.
+func Blind(pw []byte, x *ristretto.Scalar, server bool) *ristretto.Point {
+	var w ristretto.Scalar
+	var tmp ristretto.Point
+	passwordScalar(&w, pw)
+	ret := new(ristretto.Point).ScalarMultBase(x)
+	return ret.Add(ret, tmp.ScalarMult(constant(server), &w))
+}
+
+// Returns the shared element x*(peer - w*N) if server is false, and
+// x*(peer - w*M) if server is true, where peer is the message received
+// from the other party, x is the Scalar passed to Blind() and w is
+// derived from pw.
+func SharedElement(pw []byte, x *ristretto.Scalar, peer *ristretto.Point,
+	server bool) *ristretto.Point {
+	var w ristretto.Scalar
+	var tmp ristretto.Point
+	passwordScalar(&w, pw)
+	ret := new(ristretto.Point).Sub(peer, tmp.ScalarMult(constant(!server), &w))
+	return ret.ScalarMult(ret, x)
+}
+
+// Returns N if server is true and M otherwise.
+func constant(server bool) *ristretto.Point {
+	if server {
+		return N()
+	}
+	return M()
+}
+
+// Sets w to the Scalar derived from the password pw.  Returns w.
+func passwordScalar(w *ristretto.Scalar, pw []byte) *ristretto.Scalar {
+	return w.Derive(append([]byte(passwordDomain), pw...))
+}
@@ -0,0 +1,59 @@
+package spake2_test
+
+import (
+	"testing"
+
+	"github.com/bwesterb/go-ristretto"
+	"github.com/bwesterb/go-ristretto/spake2"
+)
+
+func exchange(clientPw, serverPw []byte) (kc, ks *ristretto.Point) {
+	var x, y ristretto.Scalar
+	x.Rand()
+	y.Rand()
+	X := spake2.Blind(clientPw, &x, false)
+	Y := spake2.Blind(serverPw, &y, true)
+	kc = spake2.SharedElement(clientPw, &x, Y, false)
+	ks = spake2.SharedElement(serverPw, &y, X, true)
+	return
+}
+
+func TestSPAKE2(t *testing.T) {
+	for i := 0; i < 20; i++ {
+		kc, ks := exchange([]byte("password"), []byte("password"))
+		if !kc.Equals(ks) {
+			t.Fatalf("Matching passwords yield different shared elements")
+		}
+		kc, ks = exchange([]byte("password"), []byte("passw0rd"))
+		if kc.Equals(ks) {
+			t.Fatalf("Different passwords yield the same shared element")
+		}
+	}
+}
+
+func TestSPAKE2Constants(t *testing.T) {
+	var zero ristretto.Point
+	zero.SetZero()
+	if !spake2.M().Equals(spake2.M()) || !spake2.N().Equals(spake2.N()) {
+		t.Fatalf("M and N are not deterministic")
+	}
+	if spake2.M().Equals(spake2.N()) || spake2.M().Equals(&zero) ||
+		spake2.N().Equals(&zero) {
+		t.Fatalf("M and N are not independent")
+	}
+}
+
+func TestBlind(t *testing.T) {
+	var x ristretto.Scalar
+	var xB ristretto.Point
+	x.Rand()
+	xB.ScalarMultBase(&x)
+	X := spake2.Blind([]byte("password"), &x, false)
+	Y := spake2.Blind([]byte("password"), &x, true)
+	if X.Equals(Y) || X.Equals(&xB) {
+		t.Fatalf("Blind does not depend on the role")
+	}
+	if X.Equals(spake2.Blind([]byte("other"), &x, false)) {
+		t.Fatalf("Blind does not depend on the password")
+	}
+}
@@ -0,0 +1,136 @@
+// A verifiable random function over the Ristretto group, in the style of
+// ECVRF (RFC 9381).
+//
+// A private key is a Scalar x and the corresponding public key is the
+// Point Y = x*B, where B is the basepoint.  The input alpha is hashed to
+// the Point H with Point.DeriveDalek(), which maps the SHA-512 digest to
+// the group with the one-way map of the ristretto255 RFC.  The output beta
+// is the SHA-512 hash of Gamma = x*H, and the 96-byte proof is
+// Gamma || c || s, a Chaum-Pedersen proof that Gamma and Y have the same
+// discrete logarithm with respect to H and B:
+//
+//	c = H'(H || Gamma || k*B || k*H)  and  s = k + c*x
+//
+// for a nonce k derived from x and H.  Here H' hashes with SHA-512 and
+// reduces modulo the group order with Scalar.Derive().  As the RFC does not
+// define a ristretto255 suite, the outputs are specific to this package.
+package vrf
+
+import (
+	"crypto/sha512"
+
+	"github.com/bwesterb/go-ristretto"
+)
+
+// Size of a proof in bytes.
+const ProofSize = 96
+
+// Domain separation strings.
+const (
+	hashToPointDomain = "go-ristretto VRF hash to point v1"
+	nonceDomain       = "go-ristretto VRF nonce v1"
+	challengeDomain   = "go-ristretto VRF challenge v1"
+	outputDomain      = "go-ristretto VRF output v1"
+)
+
+// Returns the output beta of the VRF with private key priv on input alpha,
+// together with a proof that beta is correct, which can be checked with
+// Verify() against the public key.  The nonce is derived deterministically
+// from priv and the input, so the proof is deterministic as well.
+func Prove(priv *ristretto.Scalar, alpha []byte) (beta [64]byte, proof []byte) {
+	var Y, H, Gamma, U, V ristretto.Point
+	var k, c, s ristretto.Scalar
+	var buf [32]byte
+	Y.ScalarMultBase(priv)
+	hashToPoint(&H, &Y, alpha)
+	Gamma.ScalarMult(&H, priv)
+
+	transcript := make([]byte, 0, len(nonceDomain)+64)
+	transcript = append(transcript, nonceDomain...)
+	priv.BytesInto(&buf)
+	transcript = append(transcript, buf[:]...)
+	H.BytesInto(&buf)
+	transcript = append(transcript, buf[:]...)
+	k.Derive(transcript)
+
+	U.ScalarMultBase(&k)
+	V.ScalarMult(&H, &k)
+	challenge(&c, &H, &Gamma, &U, &V)
+	s.MulAdd(&c, priv, &k)
+
+	proof = make([]byte, 0, ProofSize)
+	Gamma.BytesInto(&buf)
+	proof = append(proof, buf[:]...)
+	c.BytesInto(&buf)
+	proof = append(proof, buf[:]...)
+	s.BytesInto(&buf)
+	proof = append(proof, buf[:]...)
+	return output(&Gamma), proof
+}
+
+// Returns the output beta of the VRF with public key pub on input alpha
+// if proof is valid, and false otherwise.
+//
+// The proof and input are public, so this is not constant-time.
+func Verify(pub *ristretto.Point, alpha []byte, proof []byte) (
+	beta [64]byte, ok bool) {
+	var H, Gamma, U, V, tmp ristretto.Point
+	var c, cNeg, s, c2 ristretto.Scalar
+	var buf [32]byte
+	if len(proof) != ProofSize {
+		return beta, false
+	}
+	copy(buf[:], proof[:32])
+	if !Gamma.SetBytes(&buf) {
+		return beta, false
+	}
+	copy(buf[:], proof[32:64])
+	if c.SetCanonicalBytes(&buf) != nil {
+		return beta, false
+	}
+	copy(buf[:], proof[64:])
+	if s.SetCanonicalBytes(&buf) != nil {
+		return beta, false
+	}
+
+	hashToPoint(&H, pub, alpha)
+	cNeg.Neg(&c)
+	U.PublicDoubleScalarMultBase(&s, &cNeg, pub)
+	V.PublicScalarMult(&H, &s)
+	V.Sub(&V, tmp.PublicScalarMult(&Gamma, &c))
+	if !challenge(&c2, &H, &Gamma, &U, &V).Equals(&c) {
+		return beta, false
+	}
+	return output(&Gamma), true
+}
+
+// Sets H to the hash of the public key Y and the input alpha to the group.
+// Returns H.
+func hashToPoint(H, Y *ristretto.Point, alpha []byte) *ristretto.Point {
+	var buf [32]byte
+	transcript := make([]byte, 0, len(hashToPointDomain)+32+len(alpha))
+	transcript = append(transcript, hashToPointDomain...)
+	Y.BytesInto(&buf)
+	transcript = append(transcript, buf[:]...)
+	transcript = append(transcript, alpha...)
+	return H.DeriveDalek(transcript)
+}
+
+// Sets c to the challenge H'(H || Gamma || U || V).  Returns c.
+func challenge(c *ristretto.Scalar, H, Gamma, U, V *ristretto.Point) *ristretto.Scalar {
+	var buf [32]byte
+	transcript := make([]byte, 0, len(challengeDomain)+128)
+	transcript = append(transcript, challengeDomain...)
+	for _, P := range []*ristretto.Point{H, Gamma, U, V} {
+		P.BytesInto(&buf)
+		transcript = append(transcript, buf[:]...)
+	}
+	return c.Derive(transcript)
+}
+
+// Returns the output beta, the hash of Gamma.
+func output(Gamma *ristretto.Point) [64]byte {
+	var buf [32]byte
+	Gamma.BytesInto(&buf)
+	return sha512.Sum512(append([]byte(outputDomain), buf[:]...))
+}
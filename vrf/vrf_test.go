@@ -0,0 +1,113 @@
+package vrf_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+
+	"github.com/bwesterb/go-ristretto"
+	"github.com/bwesterb/go-ristretto/vrf"
+)
+
+func TestProveVerify(t *testing.T) {
+	var priv ristretto.Scalar
+	var pub, other ristretto.Point
+	alpha := []byte("input")
+	for i := 0; i < 50; i++ {
+		priv.Rand()
+		pub.ScalarMultBase(&priv)
+		beta, proof := vrf.Prove(&priv, alpha)
+		if len(proof) != vrf.ProofSize {
+			t.Fatalf("Proof has length %d", len(proof))
+		}
+		beta2, ok := vrf.Verify(&pub, alpha, proof)
+		if !ok {
+			t.Fatalf("Proof %x does not verify", proof)
+		}
+		if beta2 != beta {
+			t.Fatalf("Verify returns output %x != %x", beta2, beta)
+		}
+
+		if _, ok := vrf.Verify(&pub, []byte("other input"), proof); ok {
+			t.Fatalf("Proof verifies for other input")
+		}
+		other.Rand()
+		if _, ok := vrf.Verify(&other, alpha, proof); ok {
+			t.Fatalf("Proof verifies for other public key")
+		}
+		for _, j := range []int{0, 40, 80} {
+			proof[j] ^= 1
+			if _, ok := vrf.Verify(&pub, alpha, proof); ok {
+				t.Fatalf("Tampered proof verifies")
+			}
+			proof[j] ^= 1
+		}
+		if _, ok := vrf.Verify(&pub, alpha, proof[:95]); ok {
+			t.Fatalf("Truncated proof verifies")
+		}
+
+		beta3, _ := vrf.Prove(&priv, []byte("other input"))
+		if beta3 == beta {
+			t.Fatalf("Output does not depend on the input")
+		}
+	}
+}
+
+// Known-answer tests for the private key 00 01 02 ... 1e 00.
+func TestVectors(t *testing.T) {
+	var priv, pub = new(ristretto.Scalar), new(ristretto.Point)
+	var buf [32]byte
+	for i := 0; i < 31; i++ {
+		buf[i] = byte(i)
+	}
+	priv.SetBytes(&buf)
+	pub.ScalarMultBase(priv)
+
+	for _, v := range []struct{ alpha, beta, proof string }{
+		{"",
+			"1026cee96eb2c42248f655de26ab4aa5542f4628c919782bb8a6ed3b15d1fb58" +
+				"96a5b2704f7c58a1093a93dcdb72f6f25b015b9f61e27215309322721b3b2e5e",
+			"e0406523787da7566609ae55ca42d9a6980310a33117dbcb18b18d01307e7a25" +
+				"3938cb409238a4497f3c1260a683427dfdd9a0740ce1b13fc8bb1361ca394a07" +
+				"b042b63895f02395e00c94ef79f54c34b881fd09b9ae385d3f11b933f3d8f808"},
+		{"sample",
+			"8f111a357648db28249631ae9de6dfda6c0eee400048cb8a451ce2f05ab9ef02" +
+				"9e0aa51e0e1f4db469defd89dbf7ab102a9c0166c55989837951994c62055281",
+			"3ad346b7209311abdece24d71abc536033cae209520f2bdfe0b145b0bc3d9a64" +
+				"59d49fdb96821b83d93b5e0241045bfbab6702ca8c575936817a4f232dffaf05" +
+				"fa81bddd06224bf30bb443098c40180fab283fc135bdb163c67ee590e52f2409"},
+	} {
+		beta, proof := vrf.Prove(priv, []byte(v.alpha))
+		if hex.EncodeToString(beta[:]) != v.beta {
+			t.Fatalf("Prove(%q): output %x != %s", v.alpha, beta, v.beta)
+		}
+		if hex.EncodeToString(proof) != v.proof {
+			t.Fatalf("Prove(%q): proof %x != %s", v.alpha, proof, v.proof)
+		}
+		want, _ := hex.DecodeString(v.proof)
+		beta2, ok := vrf.Verify(pub, []byte(v.alpha), want)
+		if !ok || !bytes.Equal(beta2[:], beta[:]) {
+			t.Fatalf("Verify(%q) fails on test vector", v.alpha)
+		}
+	}
+}
+
+func BenchmarkProve(b *testing.B) {
+	var priv ristretto.Scalar
+	priv.Rand()
+	for n := 0; n < b.N; n++ {
+		vrf.Prove(&priv, []byte("input"))
+	}
+}
+
+func BenchmarkVerify(b *testing.B) {
+	var priv ristretto.Scalar
+	var pub ristretto.Point
+	priv.Rand()
+	pub.ScalarMultBase(&priv)
+	_, proof := vrf.Prove(&priv, []byte("input"))
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		vrf.Verify(&pub, []byte("input"), proof)
+	}
+}